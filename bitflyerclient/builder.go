@@ -0,0 +1,151 @@
+package bitflyerclient
+
+/* --- Parent order legs --- */
+func LimitLeg(side string, price, size Decimal) ParentOrder {
+	return ParentOrder{Condition_type: LIMIT, Side: side, Price: price, Size: size}
+}
+
+func MarketLeg(side string, size Decimal) ParentOrder {
+	return ParentOrder{Condition_type: MARKET, Side: side, Size: size}
+}
+
+func StopLeg(side string, trigger, size Decimal) ParentOrder {
+	return ParentOrder{Condition_type: STOP, Side: side, Trigger_price: trigger, Size: size}
+}
+
+func StopLimitLeg(side string, price, trigger, size Decimal) ParentOrder {
+	return ParentOrder{Condition_type: STOP_LIMIT, Side: side, Price: price, Trigger_price: trigger, Size: size}
+}
+
+func TrailLeg(side string, offset, size Decimal) ParentOrder {
+	return ParentOrder{Condition_type: TRAIL, Side: side, Offset: offset, Size: size}
+}
+
+func oppositeSide(side string) string {
+	switch side {
+	case BUY:
+		return SELL
+	case SELL:
+		return BUY
+	}
+	return ""
+}
+
+/*
+ * ParentOrderBuilder builds SendParentOrderParam fluently, e.g.
+ *
+ *	NewParentOrder().IfLimit(BUY, entry, size).Then().OCO(
+ *		LimitLeg(SELL, takeProfit, size),
+ *		StopLimitLeg(SELL, stopPrice, stopTrigger, size)).Build()
+ *
+ * The order method (SIMPLE, IFD, OCO or IFDOCO) follows from the legs given.
+ */
+type ParentOrderBuilder struct {
+	param  *SendParentOrderParam
+	ifLeg  *ParentOrder
+	then   bool
+	legs   []ParentOrder
+	oco    bool
+	errMsg string
+}
+
+func NewParentOrder() *ParentOrderBuilder {
+	return &ParentOrderBuilder{param: NewSendParentOrderParam()}
+}
+
+func (b *ParentOrderBuilder) fail(msg string) *ParentOrderBuilder {
+	if b.errMsg == "" {
+		b.errMsg = msg
+	}
+	return b
+}
+
+/* If sets the first leg of an IFD or IFDOCO order. */
+func (b *ParentOrderBuilder) If(leg ParentOrder) *ParentOrderBuilder {
+	if b.ifLeg != nil || 0 < len(b.legs) {
+		return b.fail("If must be the first leg")
+	}
+	b.ifLeg = &leg
+	return b
+}
+
+func (b *ParentOrderBuilder) IfLimit(side string, price, size Decimal) *ParentOrderBuilder {
+	return b.If(LimitLeg(side, price, size))
+}
+
+func (b *ParentOrderBuilder) IfMarket(side string, size Decimal) *ParentOrderBuilder {
+	return b.If(MarketLeg(side, size))
+}
+
+func (b *ParentOrderBuilder) IfStop(side string, trigger, size Decimal) *ParentOrderBuilder {
+	return b.If(StopLeg(side, trigger, size))
+}
+
+func (b *ParentOrderBuilder) IfStopLimit(side string, price, trigger, size Decimal) *ParentOrderBuilder {
+	return b.If(StopLimitLeg(side, price, trigger, size))
+}
+
+func (b *ParentOrderBuilder) IfTrail(side string, offset, size Decimal) *ParentOrderBuilder {
+	return b.If(TrailLeg(side, offset, size))
+}
+
+func (b *ParentOrderBuilder) Then() *ParentOrderBuilder {
+	if b.ifLeg == nil {
+		return b.fail("Then requires If")
+	}
+	b.then = true
+	return b
+}
+
+/* Order adds a single leg: a SIMPLE order, or the second leg of an IFD. */
+func (b *ParentOrderBuilder) Order(leg ParentOrder) *ParentOrderBuilder {
+	if 0 < len(b.legs) {
+		return b.fail("only one Order or OCO can be given")
+	}
+	b.legs = append(b.legs, leg)
+	return b
+}
+
+/* OCO adds two legs, the one executed first cancels the other. */
+func (b *ParentOrderBuilder) OCO(first, second ParentOrder) *ParentOrderBuilder {
+	if 0 < len(b.legs) {
+		return b.fail("only one Order or OCO can be given")
+	}
+	b.legs = append(b.legs, first, second)
+	b.oco = true
+	return b
+}
+
+func (b *ParentOrderBuilder) Build() (*SendParentOrderParam, error) {
+	if b.errMsg != "" {
+		return nil, invalid("Parameters", "%v", b.errMsg)
+	}
+	if b.ifLeg != nil && !b.then {
+		return nil, invalid("Parameters", "If requires Then")
+	}
+	if len(b.legs) == 0 {
+		return nil, invalid("Parameters", "no order given")
+	}
+
+	param := *b.param
+	param.Parameters = make([]ParentOrder, 0, 3)
+	switch {
+	case b.ifLeg != nil && b.oco:
+		param.Order_method = IFDOCO
+	case b.ifLeg != nil:
+		param.Order_method = IFD
+	case b.oco:
+		param.Order_method = OCO
+	default:
+		param.Order_method = SIMPLE
+	}
+	if b.ifLeg != nil {
+		param.Parameters = append(param.Parameters, *b.ifLeg)
+	}
+	param.Parameters = append(param.Parameters, b.legs...)
+
+	if err := param.Validate(); err != nil {
+		return nil, err
+	}
+	return &param, nil
+}
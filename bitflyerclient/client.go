@@ -1,12 +1,12 @@
 package bitflyerclient
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,9 +17,28 @@ const (
 	APIEndpointBase = "https://api.bitflyer.jp"
 )
 
+/* Product Code */
 const (
 	BTC_JPY    = "BTC_JPY"
 	FX_BTC_JPY = "FX_BTC_JPY"
+	ETH_JPY    = "ETH_JPY"
+	ETH_BTC    = "ETH_BTC"
+	BCH_BTC    = "BCH_BTC"
+)
+
+/* Product Alias of Futures */
+const (
+	BTCJPY_MAT1WK = "BTCJPY_MAT1WK"
+	BTCJPY_MAT2WK = "BTCJPY_MAT2WK"
+	BTCJPY_MAT3M  = "BTCJPY_MAT3M"
+)
+
+/* Currency Code */
+const (
+	JPY = "JPY"
+	BTC = "BTC"
+	ETH = "ETH"
+	BCH = "BCH"
 )
 
 const (
@@ -35,6 +54,17 @@ const (
 	SELL = "SELL"
 )
 
+/* Time in Force */
+type TimeInForce string
+
+const (
+	GTC TimeInForce = "GTC" /* Good 'Til Canceled */
+	IOC TimeInForce = "IOC" /* Immediate or Cancel */
+	FOK TimeInForce = "FOK" /* Fill or Kill */
+)
+
+const MaxMinuteToExpire = 43200 /* 30 days */
+
 /* Parent Order State */
 const (
 	ACTIVE    = "ACTIVE"
@@ -44,49 +74,207 @@ const (
 	REJECTED  = "REJECTED"
 )
 
+/* Deposit/Withdrawal Status */
+const (
+	PENDING = "PENDING"
+)
+
+/* Exchange Health */
+const (
+	HEALTH_NORMAL     = "NORMAL"
+	HEALTH_BUSY       = "BUSY"
+	HEALTH_VERY_BUSY  = "VERY BUSY"
+	HEALTH_SUPER_BUSY = "SUPER BUSY"
+	HEALTH_NO_ORDER   = "NO ORDER"
+	HEALTH_STOP       = "STOP"
+)
+
+/* Board State */
+const (
+	BOARD_RUNNING       = "RUNNING"
+	BOARD_CLOSED        = "CLOSED"
+	BOARD_STARTING      = "STARTING"
+	BOARD_PREOPEN       = "PREOPEN"
+	BOARD_CIRCUIT_BREAK = "CIRCUIT BREAK"
+	BOARD_AWAITING_SQ   = "AWAITING SQ"
+	BOARD_MATURED       = "MATURED"
+)
+
 type Client struct {
 	apiKey       string
 	apiSecret    string
 	endpointBase string
 	httpClient   *http.Client
 	productCode  string
+	userAgent    string
+	now          func() time.Time
+	logger       Logger
+	retryPolicy  RetryPolicy
+	rateLimit    RateLimit
+	limiter      *rateLimiter
+	roundOrders  bool
+	permissions  *permissionCache
+}
+
+/* --- Client options --- */
+type Option func(*Client)
+
+/* WithEndpoint overrides APIEndpointBase, e.g. to point at a local stub server. */
+func WithEndpoint(endpoint string) Option {
+	return func(client *Client) {
+		client.endpointBase = strings.TrimRight(endpoint, "/")
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+func WithProductCode(productCode string) Option {
+	return func(client *Client) {
+		client.productCode = productCode
+	}
 }
 
-func New(apiKey, apiSecret string) (*Client, error) {
+/* WithClock replaces time.Now as the source of ACCESS-TIMESTAMP and of ExpireAt conversions, the rate limiter keeps using time.Now. */
+func WithClock(now func() time.Time) Option {
+	return func(client *Client) {
+		client.now = now
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(client *Client) {
+		client.userAgent = userAgent
+	}
+}
+
+func New(apiKey, apiSecret string, opts ...Option) (*Client, error) {
 	c := &Client{
 		apiKey:       apiKey,
 		apiSecret:    apiSecret,
 		endpointBase: APIEndpointBase,
 		httpClient:   http.DefaultClient,
 		productCode:  FX_BTC_JPY,
+		now:          time.Now,
+		logger:       nopLogger{},
+		retryPolicy:  DefaultRetryPolicy(),
+		rateLimit:    DefaultRateLimit(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		return nil, fmt.Errorf("http client must not be nil")
 	}
+	if c.logger == nil {
+		c.logger = nopLogger{}
+	}
+	if c.now == nil {
+		return nil, fmt.Errorf("clock must not be nil")
+	}
+	/* Not c.now: a fixed WithClock in tests would never refill the buckets */
+	c.limiter = newRateLimiter(c.rateLimit, time.Now)
 	return c, nil
 }
 
+/*
+ * ForProduct returns a client bound to another default product code.
+ * It shares the HTTP client, logger, rate limiter and permission cache with the original,
+ * so it is cheap to use for endpoints without a request param (GetBoard, GetTicker, ...).
+ */
+func (client *Client) ForProduct(productCode string) *Client {
+	c := *client
+	c.productCode = productCode
+	return &c
+}
+
+func (client *Client) ProductCode() string {
+	return client.productCode
+}
+
+/* product returns productCode of a request, falling back to the client default */
+func (client *Client) product(productCode string) string {
+	if productCode != "" {
+		return productCode
+	}
+	return client.productCode
+}
+
 type requestParam struct {
 	path        string
 	method      string
 	isPrivate   bool
+	isOrder     bool
 	queryString string
 	body        string
 }
 
-func (client *Client) do(param requestParam) (*[]byte, error) {
+func (client *Client) do(ctx context.Context, param requestParam) (*[]byte, error) {
+	if err := client.checkPermission(ctx, param); err != nil {
+		return nil, err
+	}
+	policy := client.retryPolicy
+	for attempt := 1; ; attempt++ {
+		respBody, statusCode, err := client.doOnce(ctx, param)
+
+		var backoff time.Duration
+		retry := false
+		if err != nil && attempt < policy.MaxAttempts && policy.allows(param) {
+			var retryAfter time.Duration
+			retry, retryAfter = retryable(ctx, err)
+			if retry {
+				backoff = policy.backoff(attempt, retryAfter)
+			}
+		}
+		if policy.OnAttempt != nil {
+			policy.OnAttempt(RetryAttempt{
+				Method:     param.method,
+				Path:       param.path,
+				Attempt:    attempt,
+				StatusCode: statusCode,
+				Err:        err,
+				Backoff:    backoff,
+			})
+		}
+		if !retry {
+			return respBody, err
+		}
+
+		client.log(LevelWarn, "retry request",
+			Field("method", param.method), Field("path", param.path),
+			Field("attempt", attempt), Field("backoff", backoff), Field("error", err))
+		if err := sleep(ctx, backoff); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (client *Client) doOnce(ctx context.Context, param requestParam) (*[]byte, int, error) {
 	path := param.path
 	if param.queryString != "" {
 		path += "?" + param.queryString
 	}
 	url := client.endpointBase + path
 
-	req, err := http.NewRequest(param.method, url, strings.NewReader(param.body))
+	req, err := http.NewRequestWithContext(ctx, param.method, url, strings.NewReader(param.body))
 	if err != nil {
-		log.Printf("error: %v\n", err)
-		return nil, err
+		client.log(LevelError, "build request", Field("method", param.method), Field("path", param.path), Field("error", err))
+		return nil, 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if client.userAgent != "" {
+		req.Header.Set("User-Agent", client.userAgent)
+	}
+	if err := client.limiter.wait(ctx, param); err != nil {
+		client.log(LevelWarn, "rate limited", Field("method", param.method), Field("path", param.path), Field("error", err))
+		return nil, 0, err
+	}
 	if param.isPrivate {
-		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		timestamp := strconv.FormatInt(client.now().Unix(), 10)
 		text := timestamp + param.method + path + param.body
 		mac := hmac.New(sha256.New, []byte(client.apiSecret))
 		mac.Write([]byte(text))
@@ -97,27 +285,41 @@ func (client *Client) do(param requestParam) (*[]byte, error) {
 		req.Header.Set("ACCESS-SIGN", sign)
 	}
 
-	log.Printf("debug: Send request: %v %v %v\n", url, param.method, param.body)
+	client.log(LevelDebug, "send request",
+		Field("method", param.method), Field("path", path),
+		Field("header", redactHeader(req.Header)), Field("body", redactBody(param.body)))
+	start := time.Now()
 	resp, err := client.httpClient.Do(req)
+	latency := time.Since(start)
 	if err != nil {
-		log.Printf("error: %v\n", err)
-		return nil, err
+		client.log(LevelError, "request failed",
+			Field("method", param.method), Field("path", param.path),
+			Field("latency", latency), Field("error", err))
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
+	client.limiter.observe(param, resp.Header)
 
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("error: %v\n", err)
-		return nil, err
+		client.log(LevelError, "read response",
+			Field("method", param.method), Field("path", param.path),
+			Field("status", resp.StatusCode), Field("error", err))
+		return nil, resp.StatusCode, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("error: %v(%v) %v", resp.Status, resp.StatusCode, string(respBody))
-		log.Printf(err.Error())
-		return nil, err
+		apiErr := newAPIError(resp, param.method, param.path, respBody)
+		client.log(LevelError, "api error",
+			Field("method", param.method), Field("path", param.path),
+			Field("status", resp.StatusCode), Field("latency", latency), Field("error", apiErr))
+		return nil, resp.StatusCode, apiErr
 	}
 
-	log.Printf("debug: Receive Response: %v\n", string(respBody))
+	client.log(LevelInfo, "request done",
+		Field("method", param.method), Field("path", param.path),
+		Field("status", resp.StatusCode), Field("latency", latency))
+	client.log(LevelDebug, "receive response", Field("path", param.path), Field("body", string(respBody)))
 
-	return &respBody, nil
+	return &respBody, resp.StatusCode, nil
 }
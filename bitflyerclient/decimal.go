@@ -0,0 +1,212 @@
+package bitflyerclient
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/*
+ * Decimal is a fixed-point number with 8 decimal places, which covers every
+ * price, size and amount bitFlyer uses (1 satoshi = 0.00000001 BTC).
+ * It is marshalled to and from JSON numbers exactly.
+ * The range is about +/-92 billion; arithmetic does not check for overflow.
+ */
+type Decimal struct {
+	units int64 /* value * 10^DecimalPlaces */
+}
+
+const DecimalPlaces = 8
+
+const decimalScale = 100000000
+
+var bigDecimalScale = big.NewInt(decimalScale)
+
+func DecimalFromInt(i int64) Decimal {
+	return Decimal{units: i * decimalScale}
+}
+
+/* DecimalFromFloat rounds f to DecimalPlaces, half away from zero. */
+func DecimalFromFloat(f float64) Decimal {
+	return Decimal{units: int64(math.Round(f * decimalScale))}
+}
+
+/* ParseDecimal parses "123", "-0.001", "1e-05", ... rounding to DecimalPlaces. */
+func ParseDecimal(s string) (Decimal, error) {
+	if strings.ContainsAny(s, "/") {
+		return Decimal{}, fmt.Errorf("bitflyer: invalid decimal: %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("bitflyer: invalid decimal: %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(bigDecimalScale))
+	units := roundQuo(r.Num(), r.Denom())
+	if !units.IsInt64() {
+		return Decimal{}, fmt.Errorf("bitflyer: decimal out of range: %q", s)
+	}
+	return Decimal{units: units.Int64()}, nil
+}
+
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+/* roundQuo returns x/y rounded half away from zero */
+func roundQuo(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	r.Abs(r).Lsh(r, 1)
+	if r.CmpAbs(y) >= 0 {
+		if x.Sign()*y.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+/* --- Arithmetic --- */
+func (d Decimal) Add(x Decimal) Decimal {
+	return Decimal{units: d.units + x.units}
+}
+
+func (d Decimal) Sub(x Decimal) Decimal {
+	return Decimal{units: d.units - x.units}
+}
+
+/* Mul rounds the product to DecimalPlaces, half away from zero. */
+func (d Decimal) Mul(x Decimal) Decimal {
+	p := new(big.Int).Mul(big.NewInt(d.units), big.NewInt(x.units))
+	return Decimal{units: roundQuo(p, bigDecimalScale).Int64()}
+}
+
+/* Div rounds the quotient to DecimalPlaces, half away from zero. It panics if x is zero. */
+func (d Decimal) Div(x Decimal) Decimal {
+	if x.units == 0 {
+		panic("bitflyer: decimal division by zero")
+	}
+	n := new(big.Int).Mul(big.NewInt(d.units), bigDecimalScale)
+	return Decimal{units: roundQuo(n, big.NewInt(x.units)).Int64()}
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{units: -d.units}
+}
+
+func (d Decimal) Abs() Decimal {
+	if d.units < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+/* RoundDown rounds d down (toward negative infinity) to a multiple of step. */
+func (d Decimal) RoundDown(step Decimal) Decimal {
+	if step.units <= 0 {
+		return d
+	}
+	q := d.units / step.units
+	if d.units%step.units != 0 && d.units < 0 {
+		q--
+	}
+	return Decimal{units: q * step.units}
+}
+
+/* RoundUp rounds d up (toward positive infinity) to a multiple of step. */
+func (d Decimal) RoundUp(step Decimal) Decimal {
+	if step.units <= 0 {
+		return d
+	}
+	q := d.units / step.units
+	if d.units%step.units != 0 && 0 < d.units {
+		q++
+	}
+	return Decimal{units: q * step.units}
+}
+
+/* --- Comparison --- */
+func (d Decimal) Sign() int {
+	switch {
+	case d.units < 0:
+		return -1
+	case 0 < d.units:
+		return 1
+	}
+	return 0
+}
+
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+func (d Decimal) Cmp(x Decimal) int {
+	switch {
+	case d.units < x.units:
+		return -1
+	case x.units < d.units:
+		return 1
+	}
+	return 0
+}
+
+func (d Decimal) Equal(x Decimal) bool {
+	return d.units == x.units
+}
+
+func (d Decimal) LessThan(x Decimal) bool {
+	return d.units < x.units
+}
+
+func (d Decimal) GreaterThan(x Decimal) bool {
+	return d.units > x.units
+}
+
+/* --- Conversion --- */
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+func (d Decimal) String() string {
+	units := uint64(d.units)
+	sign := ""
+	if d.units < 0 {
+		sign = "-"
+		units = uint64(-d.units)
+	}
+	integer := strconv.FormatUint(units/decimalScale, 10)
+	frac := strings.TrimRight(fmt.Sprintf("%08d", units%decimalScale), "0")
+	if frac == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + frac
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+/* UnmarshalJSON accepts a JSON number, a quoted number or null. */
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), "\"")
+	if s == "null" || s == "" {
+		d.units = 0
+		return nil
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
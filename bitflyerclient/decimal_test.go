@@ -0,0 +1,227 @@
+package bitflyerclient
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		units int64
+		err   bool
+	}{
+		{"0", 0, false},
+		{"123", 12300000000, false},
+		{"-0.001", -100000, false},
+		{"0.00000001", 1, false},
+		{"1e-05", 1000, false},
+		{"1.5E3", 150000000000, false},
+		{"+2.5", 250000000, false},
+		/* Rounded to 8 places, half away from zero */
+		{"0.000000005", 1, false},
+		{"-0.000000005", -1, false},
+		{"0.0000000049", 0, false},
+		{"-0.0000000149", -1, false},
+		{"92233720368.54775807", math.MaxInt64, false},
+		{"-92233720368.54775808", math.MinInt64, false},
+		{"92233720368.54775808", 0, true},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"1/2", 0, true},
+		{"1.2.3", 0, true},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseDecimal(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && d.units != tt.units {
+			t.Errorf("ParseDecimal(%q) = %v units, want %v", tt.in, d.units, tt.units)
+		}
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	tests := []struct {
+		units int64
+		want  string
+	}{
+		{0, "0"},
+		{1, "0.00000001"},
+		{-1, "-0.00000001"},
+		{100000000, "1"},
+		{-150000000, "-1.5"},
+		{12345678900, "123.456789"},
+		{math.MaxInt64, "92233720368.54775807"},
+		{math.MinInt64, "-92233720368.54775808"},
+	}
+	for _, tt := range tests {
+		if got := (Decimal{units: tt.units}).String(); got != tt.want {
+			t.Errorf("Decimal{%v}.String() = %q, want %q", tt.units, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		op   string
+		x, y string
+		want string
+	}{
+		{"add", "0.1", "0.2", "0.3"},
+		{"sub", "0.1", "0.3", "-0.2"},
+		{"mul", "1.5", "-2", "-3"},
+		{"mul", "3000000", "0.001", "3000"},
+		/* 0.00000001 * 0.5 = 0.000000005, rounded half away from zero */
+		{"mul", "0.00000001", "0.5", "0.00000001"},
+		{"mul", "-0.00000001", "0.5", "-0.00000001"},
+		{"mul", "0.00000001", "0.49", "0"},
+		{"div", "1", "3", "0.33333333"},
+		{"div", "2", "3", "0.66666667"},
+		{"div", "-2", "3", "-0.66666667"},
+		{"div", "2", "-3", "-0.66666667"},
+		{"div", "-2", "-3", "0.66666667"},
+		{"div", "100", "0.01", "10000"},
+	}
+	for _, tt := range tests {
+		x, y := MustParseDecimal(tt.x), MustParseDecimal(tt.y)
+		var got Decimal
+		switch tt.op {
+		case "add":
+			got = x.Add(y)
+		case "sub":
+			got = x.Sub(y)
+		case "mul":
+			got = x.Mul(y)
+		case "div":
+			got = x.Div(y)
+		}
+		if got.String() != tt.want {
+			t.Errorf("%v %v %v = %v, want %v", tt.x, tt.op, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalDivByZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Div by zero did not panic")
+		}
+	}()
+	DecimalFromInt(1).Div(Decimal{})
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		d, step  string
+		down, up string
+	}{
+		{"123.456", "0.01", "123.45", "123.46"},
+		{"123.45", "0.01", "123.45", "123.45"},
+		{"-123.456", "0.01", "-123.46", "-123.45"},
+		{"-123.45", "0.01", "-123.45", "-123.45"},
+		{"0.0015", "0.001", "0.001", "0.002"},
+		{"-0.0005", "0.001", "-0.001", "0"},
+		{"1234567", "5", "1234565", "1234570"},
+		{"-1234567", "5", "-1234570", "-1234565"},
+		/* A step <= 0 leaves the value untouched */
+		{"1.23", "0", "1.23", "1.23"},
+		{"1.23", "-1", "1.23", "1.23"},
+	}
+	for _, tt := range tests {
+		d, step := MustParseDecimal(tt.d), MustParseDecimal(tt.step)
+		if got := d.RoundDown(step).String(); got != tt.down {
+			t.Errorf("%v.RoundDown(%v) = %v, want %v", tt.d, tt.step, got, tt.down)
+		}
+		if got := d.RoundUp(step).String(); got != tt.up {
+			t.Errorf("%v.RoundUp(%v) = %v, want %v", tt.d, tt.step, got, tt.up)
+		}
+	}
+}
+
+func TestDecimalCompare(t *testing.T) {
+	a, b := MustParseDecimal("-0.5"), MustParseDecimal("0.25")
+	if a.Cmp(b) != -1 || b.Cmp(a) != 1 || a.Cmp(a) != 0 {
+		t.Error("Cmp")
+	}
+	if !a.LessThan(b) || a.GreaterThan(b) || !a.Equal(MustParseDecimal("-0.50")) {
+		t.Error("LessThan/GreaterThan/Equal")
+	}
+	if a.Sign() != -1 || b.Sign() != 1 || (Decimal{}).Sign() != 0 || !(Decimal{}).IsZero() {
+		t.Error("Sign/IsZero")
+	}
+	if a.Abs() != MustParseDecimal("0.5") || a.Neg() != MustParseDecimal("0.5") {
+		t.Error("Abs/Neg")
+	}
+}
+
+func TestDecimalFloat(t *testing.T) {
+	tests := []struct {
+		f    float64
+		want string
+	}{
+		{0.1, "0.1"},
+		{0.01 + 0.02, "0.03"},
+		{-1.000000005, "-1.00000001"},
+		{123456.789, "123456.789"},
+	}
+	for _, tt := range tests {
+		d := DecimalFromFloat(tt.f)
+		if d.String() != tt.want {
+			t.Errorf("DecimalFromFloat(%v) = %v, want %v", tt.f, d, tt.want)
+		}
+		if back := MustParseDecimal(tt.want).Float64(); back != d.Float64() {
+			t.Errorf("Float64() = %v, want %v", back, d.Float64())
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type order struct {
+		Price Decimal
+		Size  Decimal
+	}
+
+	in := order{Price: MustParseDecimal("1234567.5"), Size: MustParseDecimal("-0.00000001")}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"Price":1234567.5,"Size":-0.00000001}` {
+		t.Errorf("Marshal = %s", b)
+	}
+	var out order
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{`{"price":0.1}`, "0.1", false},
+		{`{"price":"0.1"}`, "0.1", false},
+		{`{"price":1e-8}`, "0.00000001", false},
+		{`{"price":null}`, "0", false},
+		{`{"price":""}`, "0", false},
+		{`{"price":"abc"}`, "", true},
+	}
+	for _, tt := range tests {
+		out := order{Price: DecimalFromInt(7)}
+		err := json.Unmarshal([]byte(tt.in), &out)
+		if (err != nil) != tt.err {
+			t.Errorf("Unmarshal(%s) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && out.Price.String() != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, out.Price, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package bitflyerclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/* bitFlyer's "status" codes */
+const (
+	StatusMarginInsufficient = -205
+	StatusKeyNotFound        = -500
+)
+
+/*
+ * APIError is returned for every non-200 response.
+ * StatusCode is the HTTP status, Status/Error_message/Data are the fields of
+ * bitFlyer's error body, e.g. {"status":-205,"error_message":"...","data":null}.
+ */
+type APIError struct {
+	StatusCode    int             `json:"-"`
+	Method        string          `json:"-"`
+	Path          string          `json:"-"`
+	Status        int             `json:"status"`
+	Error_message string          `json:"error_message"`
+	Data          json.RawMessage `json:"data"`
+	Body          string          `json:"-"`
+	Header        http.Header     `json:"-"`
+}
+
+func newAPIError(resp *http.Response, method, path string, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       path,
+		Body:       string(body),
+		Header:     resp.Header,
+	}
+	/* Not every error (e.g. from a proxy) has a JSON body, keep the raw body then */
+	json.Unmarshal(body, apiErr)
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Error_message == "" {
+		return fmt.Sprintf("bitflyer: %v %v: %v %v %v",
+			e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+	}
+	return fmt.Sprintf("bitflyer: %v %v: %v %v: %v (status %v)",
+		e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), e.Error_message, e.Status)
+}
+
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+func IsRateLimited(err error) bool {
+	if errors.Is(err, ErrRateLimitExceeded) {
+		return true
+	}
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+func IsAuthError(err error) bool {
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+	/* Authentication failures are reported in the -5xx range */
+	return -600 < apiErr.Status && apiErr.Status <= StatusKeyNotFound
+}
+
+func IsInsufficientFunds(err error) bool {
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.Status == StatusMarginInsufficient {
+		return true
+	}
+	return strings.Contains(strings.ToLower(apiErr.Error_message), "insufficient")
+}
+
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+/* OrderNotFoundError is returned when an order can't be found by its acceptance id. */
+type OrderNotFoundError struct {
+	Acceptance_id string
+}
+
+func (e *OrderNotFoundError) Error() string {
+	return fmt.Sprintf("bitflyer: order not found: %v", e.Acceptance_id)
+}
+
+func IsOrderNotFound(err error) bool {
+	var notFoundErr *OrderNotFoundError
+	return errors.As(err, &notFoundErr)
+}
+
+/* PermissionDeniedError is returned by WithPermissionPreflight clients when the key may not call Path. */
+type PermissionDeniedError struct {
+	Path string
+}
+
+func (e *PermissionDeniedError) Error() string {
+	return fmt.Sprintf("bitflyer: permission denied: %v", e.Path)
+}
+
+func IsPermissionDenied(err error) bool {
+	var deniedErr *PermissionDeniedError
+	return errors.As(err, &deniedErr)
+}
+
+/* isClientError reports a 4xx APIError other than authentication and rate limit errors */
+func isClientError(err error) bool {
+	apiErr, ok := asAPIError(err)
+	if !ok || IsAuthError(err) || IsRateLimited(err) {
+		return false
+	}
+	return http.StatusBadRequest <= apiErr.StatusCode && apiErr.StatusCode < http.StatusInternalServerError
+}
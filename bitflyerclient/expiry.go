@@ -0,0 +1,84 @@
+package bitflyerclient
+
+import (
+	"time"
+)
+
+/* ExpiryMinutes converts d to Minute_to_expire, rounding up to whole minutes. A zero or negative d is an error. */
+func ExpiryMinutes(d time.Duration) (uint64, error) {
+	if d <= 0 {
+		return 0, invalid("Minute_to_expire", "expiry must be in the future, got %v", d)
+	}
+	return uint64((d + time.Minute - 1) / time.Minute), nil
+}
+
+/*
+orderExpiry remembers what ExpireAfter / ExpireAt were given.
+An expiry time is converted to minutes when the order is validated, by the client's clock when it is sent.
+*/
+type orderExpiry struct {
+	at  time.Time
+	err error
+}
+
+func newOrderExpiry(d time.Duration) (orderExpiry, uint64) {
+	minutes, err := ExpiryMinutes(d)
+	return orderExpiry{err: err}, minutes
+}
+
+func newOrderExpiryAt(t time.Time) orderExpiry {
+	if t.IsZero() {
+		return orderExpiry{err: invalid("Minute_to_expire", "expiry time is zero")}
+	}
+	return orderExpiry{at: t}
+}
+
+/* minutes returns Minute_to_expire as of now, minuteToExpire unless an expiry time was set */
+func (expiry orderExpiry) minutes(minuteToExpire uint64, now time.Time) (uint64, error) {
+	if expiry.err != nil {
+		return 0, expiry.err
+	}
+	if expiry.at.IsZero() {
+		return minuteToExpire, nil
+	}
+	return ExpiryMinutes(expiry.at.Sub(now))
+}
+
+func (param *SendChildOrderParam) ExpireAfter(d time.Duration) {
+	var minutes uint64
+	param.expiry, minutes = newOrderExpiry(d)
+	if param.expiry.err == nil {
+		param.Minute_to_expire = minutes
+	}
+}
+
+func (param *SendChildOrderParam) ExpireAt(t time.Time) {
+	param.expiry = newOrderExpiryAt(t)
+}
+
+func (param *SendParentOrderParam) ExpireAfter(d time.Duration) {
+	var minutes uint64
+	param.expiry, minutes = newOrderExpiry(d)
+	if param.expiry.err == nil {
+		param.Minute_to_expire = minutes
+	}
+}
+
+func (param *SendParentOrderParam) ExpireAt(t time.Time) {
+	param.expiry = newOrderExpiryAt(t)
+}
+
+func (b *ParentOrderBuilder) TimeInForce(timeInForce TimeInForce) *ParentOrderBuilder {
+	b.param.Time_in_force = timeInForce
+	return b
+}
+
+func (b *ParentOrderBuilder) ExpireAfter(d time.Duration) *ParentOrderBuilder {
+	b.param.ExpireAfter(d)
+	return b
+}
+
+func (b *ParentOrderBuilder) ExpireAt(t time.Time) *ParentOrderBuilder {
+	b.param.ExpireAt(t)
+	return b
+}
@@ -0,0 +1,102 @@
+package bitflyerclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExpiryMinutes(t *testing.T) {
+	tests := []struct {
+		d       time.Duration
+		minutes uint64
+		err     bool
+	}{
+		{time.Minute, 1, false},
+		{time.Second, 1, false},
+		{61 * time.Second, 2, false},
+		{24 * time.Hour, 1440, false},
+		{0, 0, true},
+		{-time.Minute, 0, true},
+	}
+	for _, tt := range tests {
+		minutes, err := ExpiryMinutes(tt.d)
+		if (err != nil) != tt.err || minutes != tt.minutes {
+			t.Errorf("ExpiryMinutes(%v) = %v, %v, want %v, error %v", tt.d, minutes, err, tt.minutes, tt.err)
+		}
+	}
+}
+
+func TestExpireRejectsPast(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SendChildOrderParam)
+		field  string
+	}{
+		{"ExpireAfter", func(p *SendChildOrderParam) { p.ExpireAfter(time.Hour) }, ""},
+		{"ExpireAfter 0", func(p *SendChildOrderParam) { p.ExpireAfter(0) }, "Minute_to_expire"},
+		{"ExpireAfter negative", func(p *SendChildOrderParam) { p.ExpireAfter(-time.Hour) }, "Minute_to_expire"},
+		{"ExpireAt", func(p *SendChildOrderParam) { p.ExpireAt(time.Now().Add(time.Hour)) }, ""},
+		{"ExpireAt past", func(p *SendChildOrderParam) { p.ExpireAt(time.Now().Add(-time.Hour)) }, "Minute_to_expire"},
+		{"ExpireAt zero", func(p *SendChildOrderParam) { p.ExpireAt(time.Time{}) }, "Minute_to_expire"},
+		{"ExpireAt too late", func(p *SendChildOrderParam) { p.ExpireAt(time.Now().Add(31 * 24 * time.Hour)) }, "Minute_to_expire"},
+		{"ExpireAfter replaces a past ExpireAt", func(p *SendChildOrderParam) {
+			p.ExpireAt(time.Now().Add(-time.Hour))
+			p.ExpireAfter(time.Hour)
+		}, ""},
+	}
+	for _, tt := range tests {
+		param := NewSendChildOrderParam()
+		param.Product_code = FX_BTC_JPY
+		param.Child_order_type = MARKET
+		param.Side = BUY
+		param.Size = testSize
+		tt.modify(param)
+		if field := validationField(param.Validate()); field != tt.field {
+			t.Errorf("%v: Validate() failed on %q, want %q", tt.name, field, tt.field)
+		}
+	}
+
+	_, err := NewParentOrder().Order(LimitLeg(BUY, testPrice, testSize)).ExpireAt(time.Now().Add(-time.Minute)).Build()
+	if field := validationField(err); field != "Minute_to_expire" {
+		t.Errorf("Build() with a past expiry failed on %q (%v)", field, err)
+	}
+}
+
+func TestExpireAtUsesClientClock(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var requests int
+	var sent SendChildOrderParam
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &sent); err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte(`{"child_order_acceptance_id":"JRF20200101-000000-000001"}`))
+	}, WithClock(func() time.Time { return clock }))
+
+	param := NewSendChildOrderParam()
+	param.Product_code = FX_BTC_JPY
+	param.Child_order_type = MARKET
+	param.Side = BUY
+	param.Size = testSize
+	param.ExpireAt(clock.Add(90 * time.Minute))
+	if _, err := client.SendChildOrder(param); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Minute_to_expire != 90 {
+		t.Errorf("Minute_to_expire = %v, want 90", sent.Minute_to_expire)
+	}
+
+	/* Past by the client's clock: nothing is sent */
+	param.ExpireAt(clock.Add(-time.Minute))
+	if _, err := client.SendChildOrder(param); validationField(err) != "Minute_to_expire" {
+		t.Errorf("SendChildOrder() = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("%v requests, want 1", requests)
+	}
+}
@@ -0,0 +1,249 @@
+package bitflyerclient
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const defaultPageCount = 100
+
+/* ErrCursorNotAdvancing stops an Iterator whose next page would not be older than the last one. */
+var ErrCursorNotAdvancing = errors.New("bitflyer: pagination cursor did not advance")
+
+/*
+ * Iterator walks a paged endpoint from the newest item back, following the
+ * "before" cursor transparently. Every page goes through the client's rate
+ * limiter and retry policy.
+ *
+ *	it := client.IterExecutions(ctx, nil).Since(yesterday)
+ *	for it.Next() {
+ *		exec := it.Value()
+ *	}
+ *	if err := it.Err(); err != nil {
+ *	}
+ */
+type Iterator[T any] struct {
+	ctx    context.Context
+	fetch  func(ctx context.Context, page Pagenation) ([]T, error)
+	id     func(T) int64
+	date   func(T) time.Time
+	page   Pagenation
+	stopId int64
+	since  time.Time
+	buf    []T
+	cur    T
+	err    error
+	done   bool
+}
+
+func newIterator[T any](ctx context.Context, page Pagenation,
+	fetch func(context.Context, Pagenation) ([]T, error), id func(T) int64, date func(T) time.Time) *Iterator[T] {
+	if page.Count <= 0 {
+		page.Count = defaultPageCount
+	}
+	return &Iterator[T]{
+		ctx:    ctx,
+		fetch:  fetch,
+		id:     id,
+		date:   date,
+		page:   page,
+		stopId: page.After,
+	}
+}
+
+/* StopAtId stops the iteration before the item with id (or any older one). */
+func (it *Iterator[T]) StopAtId(id int64) *Iterator[T] {
+	it.stopId = id
+	return it
+}
+
+/* Since stops the iteration at the first item older than t. */
+func (it *Iterator[T]) Since(t time.Time) *Iterator[T] {
+	it.since = t
+	return it
+}
+
+func (it *Iterator[T]) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		items, err := it.fetch(it.ctx, it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if len(items) < int(it.page.Count) {
+			it.done = true
+		}
+		if len(items) == 0 {
+			return false
+		}
+		/* A server ignoring "before" or unparsed ids would return the same page forever */
+		next := it.id(items[len(items)-1])
+		if !it.done && (next <= 0 || (0 <= it.page.Before && it.page.Before <= next)) {
+			it.err = ErrCursorNotAdvancing
+			return false
+		}
+		it.page.Before = next
+		it.buf = items
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	if (0 <= it.stopId && it.id(it.cur) <= it.stopId) || (!it.since.IsZero() && it.date(it.cur).Before(it.since)) {
+		it.done = true
+		it.buf = nil
+		return false
+	}
+	return true
+}
+
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+/* --- Iterators of paged endpoints --- */
+func (client *Client) IterExecutions(ctx context.Context, param *GetExecutionsParam) *Iterator[GetExecutionsResponse] {
+	if param == nil {
+		param = NewGetExecutionsParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetExecutionsResponse, error) {
+			p.Page = page
+			return client.GetExecutionsWithContext(ctx, &p)
+		},
+		func(exec GetExecutionsResponse) int64 { return exec.Id },
+		func(exec GetExecutionsResponse) time.Time { return exec.Exec_date.Time })
+}
+
+func (client *Client) IterPublicExecutions(ctx context.Context, param *GetPublicExecutionsParam) *Iterator[GetPublicExecutionsResponse] {
+	if param == nil {
+		param = NewGetPublicExecutionsParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetPublicExecutionsResponse, error) {
+			p.Page = page
+			return client.GetPublicExecutionsWithContext(ctx, &p)
+		},
+		func(exec GetPublicExecutionsResponse) int64 { return exec.Id },
+		func(exec GetPublicExecutionsResponse) time.Time { return exec.Exec_date.Time })
+}
+
+func (client *Client) IterChildOrders(ctx context.Context, param *GetChildOrdersParam) *Iterator[GetChildOrdersResponse] {
+	if param == nil {
+		param = NewGetChildOrdersParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetChildOrdersResponse, error) {
+			p.Page = page
+			return client.GetChildOrdersWithContext(ctx, &p)
+		},
+		func(order GetChildOrdersResponse) int64 { return order.Id },
+		func(order GetChildOrdersResponse) time.Time { return order.Child_order_date.Time })
+}
+
+func (client *Client) IterParentOrders(ctx context.Context, param *GetParentOrdersParam) *Iterator[GetParentOrdersResponse] {
+	if param == nil {
+		param = NewGetParentOrdersParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetParentOrdersResponse, error) {
+			p.Page = page
+			return client.GetParentOrdersWithContext(ctx, &p)
+		},
+		func(order GetParentOrdersResponse) int64 { return order.Id },
+		func(order GetParentOrdersResponse) time.Time { return order.Parent_order_date.Time })
+}
+
+func (client *Client) IterCollateralHistory(ctx context.Context, param *GetCollateralHistoryParam) *Iterator[GetCollateralHistoryResponse] {
+	if param == nil {
+		param = NewGetCollateralHistoryParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetCollateralHistoryResponse, error) {
+			p.Page = page
+			return client.GetCollateralHistoryWithContext(ctx, &p)
+		},
+		func(history GetCollateralHistoryResponse) int64 { return history.Id },
+		func(history GetCollateralHistoryResponse) time.Time { return history.Date.Time })
+}
+
+func (client *Client) IterBalanceHistory(ctx context.Context, param *GetBalanceHistoryParam) *Iterator[GetBalanceHistoryResponse] {
+	if param == nil {
+		param = NewGetBalanceHistoryParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetBalanceHistoryResponse, error) {
+			p.Page = page
+			return client.GetBalanceHistoryWithContext(ctx, &p)
+		},
+		func(history GetBalanceHistoryResponse) int64 { return history.Id },
+		func(history GetBalanceHistoryResponse) time.Time { return history.Event_date.Time })
+}
+
+func (client *Client) IterCoinIns(ctx context.Context, param *GetCoinInsParam) *Iterator[GetCoinInsResponse] {
+	if param == nil {
+		param = NewGetCoinInsParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetCoinInsResponse, error) {
+			p.Page = page
+			return client.GetCoinInsWithContext(ctx, &p)
+		},
+		func(coinIn GetCoinInsResponse) int64 { return coinIn.Id },
+		func(coinIn GetCoinInsResponse) time.Time { return coinIn.Event_date.Time })
+}
+
+func (client *Client) IterCoinOuts(ctx context.Context, param *GetCoinOutsParam) *Iterator[GetCoinOutsResponse] {
+	if param == nil {
+		param = NewGetCoinOutsParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetCoinOutsResponse, error) {
+			p.Page = page
+			return client.GetCoinOutsWithContext(ctx, &p)
+		},
+		func(coinOut GetCoinOutsResponse) int64 { return coinOut.Id },
+		func(coinOut GetCoinOutsResponse) time.Time { return coinOut.Event_date.Time })
+}
+
+func (client *Client) IterDeposits(ctx context.Context, param *GetDepositsParam) *Iterator[GetDepositsResponse] {
+	if param == nil {
+		param = NewGetDepositsParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetDepositsResponse, error) {
+			p.Page = page
+			return client.GetDepositsWithContext(ctx, &p)
+		},
+		func(deposit GetDepositsResponse) int64 { return deposit.Id },
+		func(deposit GetDepositsResponse) time.Time { return deposit.Event_date.Time })
+}
+
+func (client *Client) IterWithdrawals(ctx context.Context, param *GetWithdrawalsParam) *Iterator[GetWithdrawalsResponse] {
+	if param == nil {
+		param = NewGetWithdrawalsParam()
+	}
+	p := *param
+	return newIterator(ctx, p.Page,
+		func(ctx context.Context, page Pagenation) ([]GetWithdrawalsResponse, error) {
+			p.Page = page
+			return client.GetWithdrawalsWithContext(ctx, &p)
+		},
+		func(withdrawal GetWithdrawalsResponse) int64 { return withdrawal.Id },
+		func(withdrawal GetWithdrawalsResponse) time.Time { return withdrawal.Event_date.Time })
+}
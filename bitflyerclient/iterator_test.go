@@ -0,0 +1,147 @@
+package bitflyerclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	id   int64
+	date time.Time
+}
+
+var testEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+/* testItems lists items n..1 newest first, item i is dated i hours after testEpoch */
+func testItems(n int64) []testItem {
+	items := make([]testItem, 0, n)
+	for id := n; 0 < id; id-- {
+		items = append(items, testItem{id: id, date: testEpoch.Add(time.Duration(id) * time.Hour)})
+	}
+	return items
+}
+
+/* pagedFetch serves items like the REST API, recording the "before" of every call */
+func pagedFetch(items []testItem, befores *[]int64) func(context.Context, Pagenation) ([]testItem, error) {
+	return func(ctx context.Context, page Pagenation) ([]testItem, error) {
+		*befores = append(*befores, page.Before)
+		result := make([]testItem, 0)
+		for _, item := range items {
+			if 0 <= page.Before && page.Before <= item.id {
+				continue
+			}
+			if int64(len(result)) == page.Count {
+				break
+			}
+			result = append(result, item)
+		}
+		return result, nil
+	}
+}
+
+func TestIterator(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	tests := []struct {
+		name    string
+		n       int64
+		after   int64
+		modify  func(*Iterator[testItem])
+		fetch   func([]testItem, *[]int64) func(context.Context, Pagenation) ([]testItem, error)
+		ids     []int64
+		befores []int64
+		err     error
+	}{
+		{"short final page", 7, -1, nil, pagedFetch,
+			[]int64{7, 6, 5, 4, 3, 2, 1}, []int64{-1, 5, 2}, nil},
+		{"empty final page", 6, -1, nil, pagedFetch,
+			[]int64{6, 5, 4, 3, 2, 1}, []int64{-1, 4, 1}, nil},
+		{"no items", 0, -1, nil, pagedFetch, []int64{}, []int64{-1}, nil},
+		{"StopAtId", 7, -1, func(it *Iterator[testItem]) { it.StopAtId(3) }, pagedFetch,
+			[]int64{7, 6, 5, 4}, []int64{-1, 5}, nil},
+		{"after stops like StopAtId", 7, 3, nil, pagedFetch,
+			[]int64{7, 6, 5, 4}, []int64{-1, 5}, nil},
+		{"Since", 7, -1, func(it *Iterator[testItem]) { it.Since(testEpoch.Add(5 * time.Hour)) }, pagedFetch,
+			[]int64{7, 6, 5}, []int64{-1, 5}, nil},
+		{"cursor ignored by the server", 7, -1, nil,
+			func(items []testItem, befores *[]int64) func(context.Context, Pagenation) ([]testItem, error) {
+				fetch := pagedFetch(items, befores)
+				return func(ctx context.Context, page Pagenation) ([]testItem, error) {
+					page.Before = -1
+					return fetch(ctx, page)
+				}
+			},
+			[]int64{7, 6, 5}, []int64{-1, -1}, ErrCursorNotAdvancing},
+		{"ids not parsed", 7, -1, nil,
+			func(items []testItem, befores *[]int64) func(context.Context, Pagenation) ([]testItem, error) {
+				return func(ctx context.Context, page Pagenation) ([]testItem, error) {
+					*befores = append(*befores, page.Before)
+					return make([]testItem, page.Count), nil
+				}
+			},
+			[]int64{}, []int64{-1}, ErrCursorNotAdvancing},
+		{"fetch error", 7, -1, nil,
+			func(items []testItem, befores *[]int64) func(context.Context, Pagenation) ([]testItem, error) {
+				fetch := pagedFetch(items, befores)
+				return func(ctx context.Context, page Pagenation) ([]testItem, error) {
+					if 0 <= page.Before {
+						*befores = append(*befores, page.Before)
+						return nil, errFetch
+					}
+					return fetch(ctx, page)
+				}
+			},
+			[]int64{7, 6, 5}, []int64{-1, 5}, errFetch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var page Pagenation
+			page.init()
+			page.Count = 3
+			page.After = tt.after
+			befores := make([]int64, 0)
+			it := newIterator(context.Background(), page, tt.fetch(testItems(tt.n), &befores),
+				func(item testItem) int64 { return item.id },
+				func(item testItem) time.Time { return item.date })
+			if tt.modify != nil {
+				tt.modify(it)
+			}
+
+			ids := make([]int64, 0)
+			for it.Next() {
+				ids = append(ids, it.Value().id)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.ids) {
+				t.Errorf("ids %v, want %v", ids, tt.ids)
+			}
+			if fmt.Sprint(befores) != fmt.Sprint(tt.befores) {
+				t.Errorf("before %v, want %v", befores, tt.befores)
+			}
+			if !errors.Is(it.Err(), tt.err) {
+				t.Errorf("Err() = %v, want %v", it.Err(), tt.err)
+			}
+			/* A finished iterator stays finished */
+			if it.Next() {
+				t.Error("Next() after the end = true")
+			}
+		})
+	}
+}
+
+func TestIteratorDefaultPageCount(t *testing.T) {
+	var page Pagenation
+	page.init()
+	var counts []int64
+	it := newIterator(context.Background(), page,
+		func(ctx context.Context, page Pagenation) ([]testItem, error) {
+			counts = append(counts, page.Count)
+			return nil, nil
+		},
+		func(item testItem) int64 { return item.id },
+		func(item testItem) time.Time { return item.date })
+	if it.Next() || len(counts) != 1 || counts[0] != defaultPageCount {
+		t.Errorf("page counts %v, want [%v]", counts, defaultPageCount)
+	}
+}
@@ -0,0 +1,143 @@
+package bitflyerclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+/* --- Log levels --- */
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (level LogLevel) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(level))
+}
+
+/* --- Structured field --- */
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+func Field(key string, value interface{}) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+/*
+ * Logger receives every log record of the Client.
+ * The default logger discards everything; use WithLogger to enable logging.
+ */
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...LogField)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(level LogLevel, msg string, fields ...LogField) {}
+
+/*
+ * NewStdLogger writes records at or above minLevel as
+ * "<level>: <msg> key=value ...", which works with log-level prefix
+ * parsers such as colog. A nil *log.Logger writes to the standard logger.
+ */
+func NewStdLogger(logger *log.Logger, minLevel LogLevel) Logger {
+	return &stdLogger{logger: logger, minLevel: minLevel}
+}
+
+/* Output, Log and Client.log are skipped so that Lshortfile shows the real call site */
+const stdLoggerCallDepth = 3
+
+type stdLogger struct {
+	logger   *log.Logger
+	minLevel LogLevel
+}
+
+func (l *stdLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	if level < l.minLevel {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(level.String())
+	b.WriteString(": ")
+	b.WriteString(msg)
+	for _, field := range fields {
+		fmt.Fprintf(&b, " %v=%v", field.Key, field.Value)
+	}
+	if l.logger == nil {
+		log.Output(stdLoggerCallDepth, b.String())
+		return
+	}
+	l.logger.Output(stdLoggerCallDepth, b.String())
+}
+
+func WithLogger(logger Logger) Option {
+	return func(client *Client) {
+		client.logger = logger
+	}
+}
+
+/* log must be called directly by the code logging, see stdLoggerCallDepth */
+func (client *Client) log(level LogLevel, msg string, fields ...LogField) {
+	client.logger.Log(level, msg, fields...)
+}
+
+/* --- Redaction --- */
+const redacted = "[REDACTED]"
+
+var redactedHeaders = []string{"ACCESS-KEY", "ACCESS-SIGN"}
+
+func redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, key := range redactedHeaders {
+		if h.Get(key) != "" {
+			h.Set(key, redacted)
+		}
+	}
+	return h
+}
+
+/* Two-factor authentication codes, e.g. of /v1/me/withdraw */
+var redactedBodyFields = []string{"code"}
+
+func redactBody(body string) string {
+	sensitive := false
+	for _, key := range redactedBodyFields {
+		sensitive = sensitive || strings.Contains(body, "\""+key+"\"")
+	}
+	if !sensitive {
+		return body
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		return redacted
+	}
+	for _, key := range redactedBodyFields {
+		if _, ok := fields[key]; ok {
+			fields[key], _ = json.Marshal(redacted)
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
@@ -0,0 +1,55 @@
+package bitflyerclient
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStdLoggerCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	client, err := New("key", "secret", WithLogger(NewStdLogger(log.New(&buf, "", log.Lshortfile), LevelInfo)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.log(LevelDebug, "hidden")
+	client.log(LevelWarn, "shown", Field("path", "/v1/me/getbalance"))
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "logger_test.go:") || strings.Count(got, "\n") != 1 {
+		t.Fatalf("log = %q, want one line from logger_test.go", got)
+	}
+	if !strings.Contains(got, "warn: shown path=/v1/me/getbalance") {
+		t.Errorf("log = %q", got)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	header := http.Header{}
+	header.Set("ACCESS-KEY", "key")
+	header.Set("ACCESS-SIGN", "sign")
+	header.Set("ACCESS-TIMESTAMP", "1")
+	redactedHeader := redactHeader(header)
+	if redactedHeader.Get("ACCESS-KEY") != redacted || redactedHeader.Get("ACCESS-SIGN") != redacted || redactedHeader.Get("ACCESS-TIMESTAMP") != "1" {
+		t.Errorf("redactHeader = %v", redactedHeader)
+	}
+	if header.Get("ACCESS-KEY") != "key" {
+		t.Error("redactHeader modified its argument")
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		{``, ``},
+		{`{"product_code":"BTC_JPY"}`, `{"product_code":"BTC_JPY"}`},
+		{`{"amount":100,"code":"012345"}`, `{"amount":100,"code":"[REDACTED]"}`},
+		{`{"code":`, redacted},
+	}
+	for _, tt := range tests {
+		if got := redactBody(tt.in); got != tt.want {
+			t.Errorf("redactBody(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package bitflyerclient
 
 import (
+	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -51,50 +51,775 @@ const bitflyerTimeLayout = "2006-01-02T15:04:05.999"
 
 func (bt *BitflyerTime) UnmarshalJSON(b []byte) (err error) {
 	s := strings.Trim(string(b), "\"")
+	if s == "null" || s == "" {
+		bt.Time = time.Time{}
+		return nil
+	}
+	/* Realtime API appends "Z" (and 7 fractional digits), REST API doesn't */
+	s = strings.TrimSuffix(s, "Z")
 	bt.Time, err = time.Parse(bitflyerTimeLayout, s)
 	return err
 }
 
-/* ==============================
- *  Public API
- * ==============================
- */
+/* ==============================
+ *  Public API
+ * ==============================
+ */
+
+/* --- Get Order Book (Board) --- */
+type BoardOrder struct {
+	Price Decimal
+	Size  Decimal
+}
+
+type GetBoardResponse struct {
+	Mid_price Decimal
+	Bids      []BoardOrder
+	Asks      []BoardOrder
+}
+
+func (client *Client) GetBoard() (*GetBoardResponse, error) {
+	return client.GetBoardWithContext(context.Background())
+}
+
+func (client *Client) GetBoardWithContext(ctx context.Context) (*GetBoardResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/getboard",
+		method:    http.MethodGet,
+		isPrivate: false,
+	}
+	queries := url.Values{}
+	queries.Add("product_code", client.productCode)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GetBoardResponse
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return &result, err
+}
+
+/* --- Market List --- */
+type GetMarketsResponse struct {
+	Product_code string
+	Alias        string
+	Market_type  string
+}
+
+func (client *Client) GetMarkets() ([]GetMarketsResponse, error) {
+	return client.GetMarketsWithContext(context.Background())
+}
+
+func (client *Client) GetMarketsWithContext(ctx context.Context) ([]GetMarketsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/getmarkets",
+		method:    http.MethodGet,
+		isPrivate: false,
+	}
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetMarketsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Ticker --- */
+type GetTickerResponse struct {
+	Product_code      string
+	State             string
+	Timestamp         BitflyerTime
+	Tick_id           int64
+	Best_bid          Decimal
+	Best_ask          Decimal
+	Best_bid_size     Decimal
+	Best_ask_size     Decimal
+	Total_bid_depth   Decimal
+	Total_ask_depth   Decimal
+	Market_bid_size   Decimal
+	Market_ask_size   Decimal
+	Ltp               Decimal
+	Volume            Decimal
+	Volume_by_product Decimal
+}
+
+func (client *Client) GetTicker() (*GetTickerResponse, error) {
+	return client.GetTickerWithContext(context.Background())
+}
+
+func (client *Client) GetTickerWithContext(ctx context.Context) (*GetTickerResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/getticker",
+		method:    http.MethodGet,
+		isPrivate: false,
+	}
+	queries := url.Values{}
+	queries.Add("product_code", client.productCode)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GetTickerResponse
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return &result, err
+}
+
+/* --- Execution History (public) --- */
+type GetPublicExecutionsParam struct {
+	Product_code string
+	Page         Pagenation
+}
+
+func NewGetPublicExecutionsParam() *GetPublicExecutionsParam {
+	var param GetPublicExecutionsParam
+	param.Page.init()
+	return &param
+}
+
+type GetPublicExecutionsResponse struct {
+	Id                             int64
+	Side                           string
+	Price                          Decimal
+	Size                           Decimal
+	Exec_date                      BitflyerTime
+	Buy_child_order_acceptance_id  string
+	Sell_child_order_acceptance_id string
+}
+
+func (client *Client) GetPublicExecutions(param *GetPublicExecutionsParam) ([]GetPublicExecutionsResponse, error) {
+	return client.GetPublicExecutionsWithContext(context.Background(), param)
+}
+
+func (client *Client) GetPublicExecutionsWithContext(ctx context.Context, param *GetPublicExecutionsParam) ([]GetPublicExecutionsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/getexecutions",
+		method:    http.MethodGet,
+		isPrivate: false,
+	}
+	queries := url.Values{}
+	queries.Add("product_code", client.product(param.Product_code))
+	queries = addPagenation(queries, param.Page)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetPublicExecutionsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Orderbook status --- */
+type BoardStateData struct {
+	Special_quotation Decimal
+}
+
+type GetBoardStateResponse struct {
+	Health string
+	State  string
+	Data   BoardStateData
+}
+
+func (client *Client) GetBoardState() (*GetBoardStateResponse, error) {
+	return client.GetBoardStateWithContext(context.Background())
+}
+
+func (client *Client) GetBoardStateWithContext(ctx context.Context) (*GetBoardStateResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/getboardstate",
+		method:    http.MethodGet,
+		isPrivate: false,
+	}
+	queries := url.Values{}
+	queries.Add("product_code", client.productCode)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GetBoardStateResponse
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return &result, err
+}
+
+/* --- Exchange status --- */
+type GetHealthResponse struct {
+	Status string
+}
+
+func (client *Client) GetHealth() (*GetHealthResponse, error) {
+	return client.GetHealthWithContext(context.Background())
+}
+
+func (client *Client) GetHealthWithContext(ctx context.Context) (*GetHealthResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/gethealth",
+		method:    http.MethodGet,
+		isPrivate: false,
+	}
+	queries := url.Values{}
+	queries.Add("product_code", client.productCode)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GetHealthResponse
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return &result, err
+}
+
+/* ==============================
+ *  Asset API
+ * ==============================
+ */
+
+/* --- Get Account Asset Balance --- */
+type GetBalanceResponse struct {
+	Currency_code string
+	Amount        Decimal
+	Available     Decimal
+}
+
+func (client *Client) GetBalance() ([]GetBalanceResponse, error) {
+	return client.GetBalanceWithContext(context.Background())
+}
+
+func (client *Client) GetBalanceWithContext(ctx context.Context) ([]GetBalanceResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getbalance",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetBalanceResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Balance History --- */
+type GetBalanceHistoryParam struct {
+	Currency_code string
+	Page          Pagenation
+}
+
+func NewGetBalanceHistoryParam() *GetBalanceHistoryParam {
+	var param GetBalanceHistoryParam
+	param.Page.init()
+	return &param
+}
+
+type GetBalanceHistoryResponse struct {
+	Id            int64
+	Trade_date    BitflyerTime
+	Event_date    BitflyerTime
+	Product_code  string
+	Currency_code string
+	Trade_type    string
+	Price         Decimal
+	Amount        Decimal
+	Quantity      Decimal
+	Commission    Decimal
+	Balance       Decimal
+	Order_id      string
+}
+
+func (client *Client) GetBalanceHistory(param *GetBalanceHistoryParam) ([]GetBalanceHistoryResponse, error) {
+	return client.GetBalanceHistoryWithContext(context.Background(), param)
+}
+
+func (client *Client) GetBalanceHistoryWithContext(ctx context.Context, param *GetBalanceHistoryParam) ([]GetBalanceHistoryResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getbalancehistory",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+	queries := url.Values{}
+	if param.Currency_code != "" {
+		queries.Add("currency_code", param.Currency_code)
+	}
+	queries = addPagenation(queries, param.Page)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetBalanceHistoryResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Margin Status --- */
+type GetCollateralResponse struct {
+	Collateral         Decimal
+	Open_position_pnl  Decimal
+	Require_collateral Decimal
+	Keep_rate          float64
+}
+
+func (client *Client) GetCollateral() (*GetCollateralResponse, error) {
+	return client.GetCollateralWithContext(context.Background())
+}
+
+func (client *Client) GetCollateralWithContext(ctx context.Context) (*GetCollateralResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getcollateral",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GetCollateralResponse
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return &result, err
+}
+
+/* --- Get Margin Status by Currency --- */
+type GetCollateralAccountsResponse struct {
+	Currency_code string
+	Amount        Decimal
+}
+
+func (client *Client) GetCollateralAccounts() ([]GetCollateralAccountsResponse, error) {
+	return client.GetCollateralAccountsWithContext(context.Background())
+}
+
+func (client *Client) GetCollateralAccountsWithContext(ctx context.Context) ([]GetCollateralAccountsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getcollateralaccounts",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetCollateralAccountsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Margin Change History --- */
+type GetCollateralHistoryParam struct {
+	Page Pagenation
+}
+
+func NewGetCollateralHistoryParam() *GetCollateralHistoryParam {
+	var param GetCollateralHistoryParam
+	param.Page.init()
+	return &param
+}
+
+type GetCollateralHistoryResponse struct {
+	Id            int64
+	Currency_code string
+	Change        Decimal
+	Amount        Decimal
+	Reason_code   string
+	Date          BitflyerTime
+}
+
+func (client *Client) GetCollateralHistory(param *GetCollateralHistoryParam) ([]GetCollateralHistoryResponse, error) {
+	return client.GetCollateralHistoryWithContext(context.Background(), param)
+}
+
+func (client *Client) GetCollateralHistoryWithContext(ctx context.Context, param *GetCollateralHistoryParam) ([]GetCollateralHistoryResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getcollateralhistory",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+	queries := url.Values{}
+	queries = addPagenation(queries, param.Page)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetCollateralHistoryResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* ==============================
+ *  Deposit/Withdrawal API
+ * ==============================
+ */
+
+/* --- Get Crypto Assets Deposit Addresses --- */
+type GetAddressesResponse struct {
+	Type          string
+	Currency_code string
+	Address       string
+}
+
+func (client *Client) GetAddresses() ([]GetAddressesResponse, error) {
+	return client.GetAddressesWithContext(context.Background())
+}
+
+func (client *Client) GetAddressesWithContext(ctx context.Context) ([]GetAddressesResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getaddresses",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetAddressesResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Crypto Assets Deposit History --- */
+type GetCoinInsParam struct {
+	Page Pagenation
+}
+
+func NewGetCoinInsParam() *GetCoinInsParam {
+	var param GetCoinInsParam
+	param.Page.init()
+	return &param
+}
+
+type GetCoinInsResponse struct {
+	Id            int64
+	Order_id      string
+	Currency_code string
+	Amount        Decimal
+	Address       string
+	Tx_hash       string
+	Status        string
+	Event_date    BitflyerTime
+}
+
+func (client *Client) GetCoinIns(param *GetCoinInsParam) ([]GetCoinInsResponse, error) {
+	return client.GetCoinInsWithContext(context.Background(), param)
+}
+
+func (client *Client) GetCoinInsWithContext(ctx context.Context, param *GetCoinInsParam) ([]GetCoinInsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getcoinins",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+	queries := url.Values{}
+	queries = addPagenation(queries, param.Page)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetCoinInsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Crypto Assets Transaction History --- */
+type GetCoinOutsParam struct {
+	Page Pagenation
+}
 
-/* --- Get Order Book (Board) --- */
-type BoardOrder struct {
-	Price float64
-	Size  float64
+func NewGetCoinOutsParam() *GetCoinOutsParam {
+	var param GetCoinOutsParam
+	param.Page.init()
+	return &param
 }
 
-type GetBoardResponse struct {
-	Mid_price float64
-	Bids      []BoardOrder
-	Asks      []BoardOrder
+type GetCoinOutsResponse struct {
+	Id             int64
+	Order_id       string
+	Currency_code  string
+	Amount         Decimal
+	Address        string
+	Tx_hash        string
+	Fee            Decimal
+	Additional_fee Decimal
+	Status         string
+	Event_date     BitflyerTime
 }
 
-func (client *Client) GetBoard() (*GetBoardResponse, error) {
+func (client *Client) GetCoinOuts(param *GetCoinOutsParam) ([]GetCoinOutsResponse, error) {
+	return client.GetCoinOutsWithContext(context.Background(), param)
+}
+
+func (client *Client) GetCoinOutsWithContext(ctx context.Context, param *GetCoinOutsParam) ([]GetCoinOutsResponse, error) {
 	reqParam := requestParam{
-		path:      "/v1/getboard",
+		path:      "/v1/me/getcoinouts",
 		method:    http.MethodGet,
-		isPrivate: false,
+		isPrivate: true,
 	}
 	queries := url.Values{}
-	queries.Add("product_code", string(client.productCode))
+	queries = addPagenation(queries, param.Page)
 	reqParam.queryString = queries.Encode()
 
-	respBody, err := client.do(reqParam)
+	respBody, err := client.do(ctx, reqParam)
 	if err != nil {
 		return nil, err
 	}
 
-	var result GetBoardResponse
+	result := make([]GetCoinOutsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Summary of Bank Accounts --- */
+type GetBankAccountsResponse struct {
+	Id             int64
+	Is_verified    bool
+	Bank_name      string
+	Branch_name    string
+	Account_type   string
+	Account_number string
+	Account_name   string
+}
+
+func (client *Client) GetBankAccounts() ([]GetBankAccountsResponse, error) {
+	return client.GetBankAccountsWithContext(context.Background())
+}
+
+func (client *Client) GetBankAccountsWithContext(ctx context.Context) ([]GetBankAccountsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getbankaccounts",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetBankAccountsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Cash Deposits --- */
+type GetDepositsParam struct {
+	Page Pagenation
+}
+
+func NewGetDepositsParam() *GetDepositsParam {
+	var param GetDepositsParam
+	param.Page.init()
+	return &param
+}
+
+type GetDepositsResponse struct {
+	Id            int64
+	Order_id      string
+	Currency_code string
+	Amount        Decimal
+	Status        string
+	Event_date    BitflyerTime
+}
+
+func (client *Client) GetDeposits(param *GetDepositsParam) ([]GetDepositsResponse, error) {
+	return client.GetDepositsWithContext(context.Background(), param)
+}
+
+func (client *Client) GetDepositsWithContext(ctx context.Context, param *GetDepositsParam) ([]GetDepositsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getdeposits",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+	queries := url.Values{}
+	queries = addPagenation(queries, param.Page)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetDepositsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Withdrawing Funds --- */
+type WithdrawParam struct {
+	Currency_code   string  `json:"currency_code"`
+	Bank_account_id int64   `json:"bank_account_id"`
+	Amount          Decimal `json:"amount"`
+	Code            string  `json:"code,omitempty"` /* two-factor authentication code */
+}
+
+type WithdrawResponse struct {
+	Message_id string
+}
+
+/*
+ * Withdraw is never retried unless RetryPolicy.RetryNonIdempotent is set,
+ * and Code is redacted from debug logs.
+ */
+func (client *Client) Withdraw(param *WithdrawParam) (*WithdrawResponse, error) {
+	return client.WithdrawWithContext(context.Background(), param)
+}
+
+func (client *Client) WithdrawWithContext(ctx context.Context, param *WithdrawParam) (*WithdrawResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/withdraw",
+		method:    http.MethodPost,
+		isPrivate: true,
+	}
+
+	bodyJson, err := json.Marshal(param)
+	if err != nil {
+		client.log(LevelError, "marshal request", Field("path", reqParam.path), Field("error", err))
+		return nil, err
+	}
+
+	reqParam.body = string(bodyJson)
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var result WithdrawResponse
 	if err := json.Unmarshal(*respBody, &result); err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
 	}
 
 	return &result, err
 }
 
+/* --- Get Withdrawal History --- */
+type GetWithdrawalsParam struct {
+	Page       Pagenation
+	Message_id string
+}
+
+func NewGetWithdrawalsParam() *GetWithdrawalsParam {
+	var param GetWithdrawalsParam
+	param.Page.init()
+	return &param
+}
+
+type GetWithdrawalsResponse struct {
+	Id            int64
+	Order_id      string
+	Currency_code string
+	Amount        Decimal
+	Status        string
+	Event_date    BitflyerTime
+}
+
+func (client *Client) GetWithdrawals(param *GetWithdrawalsParam) ([]GetWithdrawalsResponse, error) {
+	return client.GetWithdrawalsWithContext(context.Background(), param)
+}
+
+func (client *Client) GetWithdrawalsWithContext(ctx context.Context, param *GetWithdrawalsParam) ([]GetWithdrawalsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getwithdrawals",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+	queries := url.Values{}
+	queries = addPagenation(queries, param.Page)
+	if param.Message_id != "" {
+		queries.Add("message_id", param.Message_id)
+	}
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetWithdrawalsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
 /* ==============================
  *  Trading API
  * ==============================
@@ -102,7 +827,10 @@ func (client *Client) GetBoard() (*GetBoardResponse, error) {
 
 /* --- Get Execution History --- */
 type GetExecutionsParam struct {
-	Page Pagenation
+	Product_code              string
+	Page                      Pagenation
+	Child_order_id            string
+	Child_order_acceptance_id string
 }
 
 func NewGetExecutionsParam() *GetExecutionsParam {
@@ -115,32 +843,42 @@ type GetExecutionsResponse struct {
 	Id                        int64
 	Child_order_id            string
 	Side                      string
-	Price                     float64
-	Size                      float64
-	Commission                float64
+	Price                     Decimal
+	Size                      Decimal
+	Commission                Decimal
 	Exec_date                 BitflyerTime
 	Child_order_acceptance_id string
 }
 
 func (client *Client) GetExecutions(param *GetExecutionsParam) ([]GetExecutionsResponse, error) {
+	return client.GetExecutionsWithContext(context.Background(), param)
+}
+
+func (client *Client) GetExecutionsWithContext(ctx context.Context, param *GetExecutionsParam) ([]GetExecutionsResponse, error) {
 	reqParam := requestParam{
 		path:      "/v1/me/getexecutions",
 		method:    http.MethodGet,
 		isPrivate: true,
 	}
 	queries := url.Values{}
-	queries.Add("product_code", string(client.productCode))
+	queries.Add("product_code", client.product(param.Product_code))
 	queries = addPagenation(queries, param.Page)
+	if param.Child_order_id != "" {
+		queries.Add("child_order_id", param.Child_order_id)
+	}
+	if param.Child_order_acceptance_id != "" {
+		queries.Add("child_order_acceptance_id", param.Child_order_acceptance_id)
+	}
 	reqParam.queryString = queries.Encode()
 
-	respBody, err := client.do(reqParam)
+	respBody, err := client.do(ctx, reqParam)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]GetExecutionsResponse, 0)
 	if err := json.Unmarshal(*respBody, &result); err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
 	}
 
 	return result, err
@@ -148,12 +886,12 @@ func (client *Client) GetExecutions(param *GetExecutionsParam) ([]GetExecutionsR
 
 /* --- Get Child Orders --- */
 type GetChildOrdersParam struct {
-	Page Pagenation
-    Product_code string
-    Child_order_state string
-    Child_order_id string
-    Child_order_acceptance_id string
-    Parent_order_id string
+	Page                      Pagenation
+	Product_code              string
+	Child_order_state         string
+	Child_order_id            string
+	Child_order_acceptance_id string
+	Parent_order_id           string
 }
 
 func NewGetChildOrdersParam() *GetChildOrdersParam {
@@ -165,53 +903,57 @@ func NewGetChildOrdersParam() *GetChildOrdersParam {
 type GetChildOrdersResponse struct {
 	Id                        int64
 	Child_order_id            string
-    Product_code              string
-    Child_order_type          string
+	Product_code              string
+	Child_order_type          string
 	Side                      string
-	Price                     float64
-    Average_price             float64
-	Size                      float64
-    Child_order_state         string
-    Expire_date               BitflyerTime
-    Child_order_date          BitflyerTime
+	Price                     Decimal
+	Average_price             Decimal
+	Size                      Decimal
+	Child_order_state         string
+	Expire_date               BitflyerTime
+	Child_order_date          BitflyerTime
 	Child_order_acceptance_id string
-    Outstanding_size          float64
-    Cancel_size               float64
-    Executed_size             float64
-    Total_commission          float64
+	Outstanding_size          Decimal
+	Cancel_size               Decimal
+	Executed_size             Decimal
+	Total_commission          Decimal
 }
 
 func (client *Client) GetChildOrders(param *GetChildOrdersParam) ([]GetChildOrdersResponse, error) {
+	return client.GetChildOrdersWithContext(context.Background(), param)
+}
+
+func (client *Client) GetChildOrdersWithContext(ctx context.Context, param *GetChildOrdersParam) ([]GetChildOrdersResponse, error) {
 	reqParam := requestParam{
 		path:      "/v1/me/getchildorders",
 		method:    http.MethodGet,
 		isPrivate: true,
 	}
 	queries := url.Values{}
-	queries.Add("product_code", string(client.productCode))
+	queries.Add("product_code", client.product(param.Product_code))
 	queries = addPagenation(queries, param.Page)
-    if param.Child_order_state != "" {
-        queries.Add("child_order_state", param.Child_order_state)
-    }
-    if param.Child_order_id != "" {
-        queries.Add("child_order_id", param.Child_order_id)
-    }
-    if param.Child_order_acceptance_id != "" {
-        queries.Add("child_order_acceptance_id", param.Child_order_acceptance_id)
-    }
-    if param.Parent_order_id != "" {
-        queries.Add("parent_order_id", param.Parent_order_id)
-    }
+	if param.Child_order_state != "" {
+		queries.Add("child_order_state", param.Child_order_state)
+	}
+	if param.Child_order_id != "" {
+		queries.Add("child_order_id", param.Child_order_id)
+	}
+	if param.Child_order_acceptance_id != "" {
+		queries.Add("child_order_acceptance_id", param.Child_order_acceptance_id)
+	}
+	if param.Parent_order_id != "" {
+		queries.Add("parent_order_id", param.Parent_order_id)
+	}
 	reqParam.queryString = queries.Encode()
 
-	respBody, err := client.do(reqParam)
+	respBody, err := client.do(ctx, reqParam)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]GetChildOrdersResponse, 0)
 	if err := json.Unmarshal(*respBody, &result); err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
 	}
 
 	return result, err
@@ -219,19 +961,20 @@ func (client *Client) GetChildOrders(param *GetChildOrdersParam) ([]GetChildOrde
 
 /* --- Send a New Order --- */
 type SendChildOrderParam struct {
-	Product_code     string  `json:"product_code"`
-	Child_order_type string  `json:"child_order_type"`
-	Side             string  `json:"side"`
-	Price            float64 `json:"price"`
-	Size             float64 `json:"size"`
-	Minute_to_expire uint64  `json:"minute_to_expire"`
-	Time_in_force    string  `json:"time_in_force"`
+	Product_code     string      `json:"product_code"`
+	Child_order_type string      `json:"child_order_type"`
+	Side             string      `json:"side"`
+	Price            Decimal     `json:"price"`
+	Size             Decimal     `json:"size"`
+	Minute_to_expire uint64      `json:"minute_to_expire"`
+	Time_in_force    TimeInForce `json:"time_in_force"`
+	expiry           orderExpiry
 }
 
 func NewSendChildOrderParam() *SendChildOrderParam {
 	var param SendChildOrderParam
-	param.Minute_to_expire = 43200
-	param.Time_in_force = "GTC"
+	param.Minute_to_expire = MaxMinuteToExpire
+	param.Time_in_force = GTC
 	return &param
 }
 
@@ -240,33 +983,80 @@ type SendChildOrderResponse struct {
 }
 
 func (client *Client) SendChildOrder(param *SendChildOrderParam) (*SendChildOrderResponse, error) {
-	param.Product_code = client.productCode
+	return client.SendChildOrderWithContext(context.Background(), param)
+}
+
+func (client *Client) SendChildOrderWithContext(ctx context.Context, param *SendChildOrderParam) (*SendChildOrderResponse, error) {
+	param.Product_code = client.product(param.Product_code)
+	now := client.now()
+	if err := param.validate(now); err != nil {
+		client.log(LevelError, "invalid order", Field("path", "/v1/me/sendchildorder"), Field("error", err))
+		return nil, err
+	}
+	param.Minute_to_expire, _ = param.expiry.minutes(param.Minute_to_expire, now)
 	var reqParam requestParam
 	reqParam.path = "/v1/me/sendchildorder"
 	reqParam.method = http.MethodPost
 	reqParam.isPrivate = true
+	reqParam.isOrder = true
 	reqParam.queryString = ""
 
 	bodyJson, err := json.Marshal(param)
 	if err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "marshal request", Field("path", reqParam.path), Field("error", err))
 		return nil, err
 	}
 
 	reqParam.body = string(bodyJson)
-	respBody, err := client.do(reqParam)
+	respBody, err := client.do(ctx, reqParam)
 	if err != nil {
 		return nil, err
 	}
 
 	var result SendChildOrderResponse
 	if err := json.Unmarshal(*respBody, &result); err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
 	}
 
 	return &result, err
 }
 
+/* --- Cancel Order --- */
+type CancelChildOrderParam struct {
+	Product_code              string `json:"product_code"`
+	Child_order_id            string `json:"child_order_id,omitempty"`
+	Child_order_acceptance_id string `json:"child_order_acceptance_id,omitempty"`
+}
+
+func NewCancelChildOrderParam() *CancelChildOrderParam {
+	var param CancelChildOrderParam
+	return &param
+}
+
+func (client *Client) CancelChildOrder(param *CancelChildOrderParam) error {
+	return client.CancelChildOrderWithContext(context.Background(), param)
+}
+
+func (client *Client) CancelChildOrderWithContext(ctx context.Context, param *CancelChildOrderParam) error {
+	param.Product_code = client.product(param.Product_code)
+	reqParam := requestParam{
+		path:      "/v1/me/cancelchildorder",
+		method:    http.MethodPost,
+		isPrivate: true,
+		isOrder:   true,
+	}
+
+	bodyJson, err := json.Marshal(param)
+	if err != nil {
+		client.log(LevelError, "marshal request", Field("path", reqParam.path), Field("error", err))
+		return err
+	}
+
+	reqParam.body = string(bodyJson)
+	_, err = client.do(ctx, reqParam)
+	return err
+}
+
 /* Submit New Parent Order (Special Order) */
 const (
 	SIMPLE = "SIMPLE"
@@ -279,23 +1069,24 @@ type ParentOrder struct {
 	Product_code   string  `json:"product_code"`
 	Condition_type string  `json:"condition_type"`
 	Side           string  `json:"side"`
-	Size           float64 `json:"size"`
-	Price          float64 `json:"price"`
-	Trigger_price  float64 `json:"trigger_price"`
-	Offset         float64 `json:"offset"`
+	Size           Decimal `json:"size"`
+	Price          Decimal `json:"price"`
+	Trigger_price  Decimal `json:"trigger_price"`
+	Offset         Decimal `json:"offset"`
 }
 
 type SendParentOrderParam struct {
 	Order_method     string        `json:"order_method"`
 	Minute_to_expire uint64        `json:"minute_to_expire"`
-	Time_in_force    string        `json:"time_in_force"`
+	Time_in_force    TimeInForce   `json:"time_in_force"`
 	Parameters       []ParentOrder `json:"parameters"`
+	expiry           orderExpiry
 }
 
 func NewSendParentOrderParam() *SendParentOrderParam {
 	var param SendParentOrderParam
-	param.Minute_to_expire = 43200
-	param.Time_in_force = "GTC"
+	param.Minute_to_expire = MaxMinuteToExpire
+	param.Time_in_force = GTC
 	param.Parameters = make([]ParentOrder, 0)
 	return &param
 }
@@ -305,36 +1096,117 @@ type SendParentOrderResponse struct {
 }
 
 func (client *Client) SendParentOrder(param *SendParentOrderParam) (*SendParentOrderResponse, error) {
-	for i, _ := range param.Parameters {
-		param.Parameters[i].Product_code = client.productCode
+	return client.SendParentOrderWithContext(context.Background(), param)
+}
+
+func (client *Client) SendParentOrderWithContext(ctx context.Context, param *SendParentOrderParam) (*SendParentOrderResponse, error) {
+	for i := range param.Parameters {
+		param.Parameters[i].Product_code = client.product(param.Parameters[i].Product_code)
+	}
+	now := client.now()
+	if err := param.validate(now); err != nil {
+		client.log(LevelError, "invalid order", Field("path", "/v1/me/sendparentorder"), Field("error", err))
+		return nil, err
 	}
+	param.Minute_to_expire, _ = param.expiry.minutes(param.Minute_to_expire, now)
 	reqParam := requestParam{
 		path:        "/v1/me/sendparentorder",
 		method:      http.MethodPost,
 		isPrivate:   true,
+		isOrder:     true,
 		queryString: "",
 	}
 
 	bodyJson, err := json.Marshal(param)
 	if err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "marshal request", Field("path", reqParam.path), Field("error", err))
 		return nil, err
 	}
 
 	reqParam.body = string(bodyJson)
-	respBody, err := client.do(reqParam)
+	respBody, err := client.do(ctx, reqParam)
 	if err != nil {
 		return nil, err
 	}
 
 	var result SendParentOrderResponse
 	if err := json.Unmarshal(*respBody, &result); err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
 	}
 
 	return &result, err
 }
 
+/* --- Cancel parent order --- */
+type CancelParentOrderParam struct {
+	Product_code               string `json:"product_code"`
+	Parent_order_id            string `json:"parent_order_id,omitempty"`
+	Parent_order_acceptance_id string `json:"parent_order_acceptance_id,omitempty"`
+}
+
+func NewCancelParentOrderParam() *CancelParentOrderParam {
+	var param CancelParentOrderParam
+	return &param
+}
+
+func (client *Client) CancelParentOrder(param *CancelParentOrderParam) error {
+	return client.CancelParentOrderWithContext(context.Background(), param)
+}
+
+func (client *Client) CancelParentOrderWithContext(ctx context.Context, param *CancelParentOrderParam) error {
+	param.Product_code = client.product(param.Product_code)
+	reqParam := requestParam{
+		path:      "/v1/me/cancelparentorder",
+		method:    http.MethodPost,
+		isPrivate: true,
+		isOrder:   true,
+	}
+
+	bodyJson, err := json.Marshal(param)
+	if err != nil {
+		client.log(LevelError, "marshal request", Field("path", reqParam.path), Field("error", err))
+		return err
+	}
+
+	reqParam.body = string(bodyJson)
+	_, err = client.do(ctx, reqParam)
+	return err
+}
+
+/* --- Cancel All Orders --- */
+type CancelAllChildOrdersParam struct {
+	Product_code string `json:"product_code"`
+}
+
+func NewCancelAllChildOrdersParam() *CancelAllChildOrdersParam {
+	var param CancelAllChildOrdersParam
+	return &param
+}
+
+func (client *Client) CancelAllChildOrders(param *CancelAllChildOrdersParam) error {
+	return client.CancelAllChildOrdersWithContext(context.Background(), param)
+}
+
+func (client *Client) CancelAllChildOrdersWithContext(ctx context.Context, param *CancelAllChildOrdersParam) error {
+	param.Product_code = client.product(param.Product_code)
+	reqParam := requestParam{
+		path:      "/v1/me/cancelallchildorders",
+		method:    http.MethodPost,
+		isPrivate: true,
+		isOrder:   true,
+	}
+
+	bodyJson, err := json.Marshal(param)
+	if err != nil {
+		client.log(LevelError, "marshal request", Field("path", reqParam.path), Field("error", err))
+		return err
+	}
+
+	reqParam.body = string(bodyJson)
+	_, err = client.do(ctx, reqParam)
+	return err
+}
+
 /* --- List Parent Orders --- */
 type GetParentOrdersParam struct {
 	Product_code       string
@@ -354,40 +1226,44 @@ type GetParentOrdersResponse struct {
 	Product_code               string
 	Side                       string
 	Parent_order_type          string
-	Price                      float64
-	Size                       float64
+	Price                      Decimal
+	Size                       Decimal
 	Parent_order_state         string
 	Expire_date                BitflyerTime
 	Parent_order_date          BitflyerTime
 	Parent_order_acceptance_id string
-	Outstanding_size           float64
-	Cancel_size                float64
-	Executed_size              float64
-	total_commission           float64
+	Outstanding_size           Decimal
+	Cancel_size                Decimal
+	Executed_size              Decimal
+	Total_commission           Decimal
 }
 
 func (client *Client) GetParentOrders(param *GetParentOrdersParam) ([]GetParentOrdersResponse, error) {
+	return client.GetParentOrdersWithContext(context.Background(), param)
+}
+
+func (client *Client) GetParentOrdersWithContext(ctx context.Context, param *GetParentOrdersParam) ([]GetParentOrdersResponse, error) {
 	reqParam := requestParam{
 		path:      "/v1/me/getparentorders",
 		method:    http.MethodGet,
 		isPrivate: true,
 	}
 	queries := url.Values{}
-	queries.Add("product_code", string(client.productCode))
+	queries.Add("product_code", client.product(param.Product_code))
 	queries = addPagenation(queries, param.Page)
 	if param.Parent_order_state != "" {
 		queries.Add("parent_order_state", param.Parent_order_state)
 	}
 	reqParam.queryString = queries.Encode()
 
-	respBody, err := client.do(reqParam)
+	respBody, err := client.do(ctx, reqParam)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]GetParentOrdersResponse, 0)
 	if err := json.Unmarshal(*respBody, &result); err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
 	}
 
 	return result, err
@@ -414,6 +1290,10 @@ type GetParentOrderResponse struct {
 }
 
 func (client *Client) GetParentOrder(param *GetParentOrderParam) (*GetParentOrderResponse, error) {
+	return client.GetParentOrderWithContext(context.Background(), param)
+}
+
+func (client *Client) GetParentOrderWithContext(ctx context.Context, param *GetParentOrderParam) (*GetParentOrderResponse, error) {
 	reqParam := requestParam{
 		path:      "/v1/me/getparentorder",
 		method:    http.MethodGet,
@@ -423,14 +1303,88 @@ func (client *Client) GetParentOrder(param *GetParentOrderParam) (*GetParentOrde
 	queries.Add("parent_order_acceptance_id", param.Parent_order_acceptance_id)
 	reqParam.queryString = queries.Encode()
 
-	respBody, err := client.do(reqParam)
+	respBody, err := client.do(ctx, reqParam)
 	if err != nil {
 		return nil, err
 	}
 
 	var result GetParentOrderResponse
 	if err := json.Unmarshal(*respBody, &result); err != nil {
-		log.Printf("error: %v\n", err)
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return &result, err
+}
+
+/* --- Get Open Interest Summary --- */
+type GetPositionsResponse struct {
+	Product_code          string
+	Side                  string
+	Price                 Decimal
+	Size                  Decimal
+	Commission            Decimal
+	Swap_point_accumulate Decimal
+	Require_collateral    Decimal
+	Open_date             BitflyerTime
+	Leverage              float64
+	Pnl                   Decimal
+	Sfd                   Decimal
+}
+
+func (client *Client) GetPositions() ([]GetPositionsResponse, error) {
+	return client.GetPositionsWithContext(context.Background())
+}
+
+func (client *Client) GetPositionsWithContext(ctx context.Context) ([]GetPositionsResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/getpositions",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+	queries := url.Values{}
+	queries.Add("product_code", client.productCode)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GetPositionsResponse, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Get Trading Commission --- */
+type GetTradingCommissionResponse struct {
+	Commission_rate float64
+}
+
+func (client *Client) GetTradingCommission() (*GetTradingCommissionResponse, error) {
+	return client.GetTradingCommissionWithContext(context.Background())
+}
+
+func (client *Client) GetTradingCommissionWithContext(ctx context.Context) (*GetTradingCommissionResponse, error) {
+	reqParam := requestParam{
+		path:      "/v1/me/gettradingcommission",
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+	queries := url.Values{}
+	queries.Add("product_code", client.productCode)
+	reqParam.queryString = queries.Encode()
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GetTradingCommissionResponse
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
 	}
 
 	return &result, err
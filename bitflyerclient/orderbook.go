@@ -0,0 +1,195 @@
+package bitflyerclient
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+var ErrInsufficientDepth = errors.New("bitflyer: not enough depth in order book")
+
+/*
+ * OrderBook is a locally maintained board.
+ * Seed it with Reset (GetBoard or a board snapshot message) and keep it
+ * up to date with Apply (board diff messages). It is safe for concurrent use.
+ */
+type OrderBook struct {
+	mu       sync.RWMutex
+	midPrice Decimal
+	bids     []BoardOrder /* best (highest) first */
+	asks     []BoardOrder /* best (lowest) first */
+}
+
+func NewOrderBook() *OrderBook {
+	return &OrderBook{}
+}
+
+func (book *OrderBook) Reset(snapshot *GetBoardResponse) {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	book.midPrice = snapshot.Mid_price
+	book.bids = book.bids[:0]
+	book.asks = book.asks[:0]
+	for _, order := range snapshot.Bids {
+		book.bids = upsertLevel(book.bids, order, true)
+	}
+	for _, order := range snapshot.Asks {
+		book.asks = upsertLevel(book.asks, order, false)
+	}
+}
+
+/*
+ * Apply merges a board diff, a level with size 0 is removed.
+ * Diffs must arrive without gaps: after a missed message (a realtime Gap of
+ * the board channel) the book is stale until it is Reset from a snapshot.
+ */
+func (book *OrderBook) Apply(diff *GetBoardResponse) {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	if !diff.Mid_price.IsZero() {
+		book.midPrice = diff.Mid_price
+	}
+	for _, order := range diff.Bids {
+		book.bids = upsertLevel(book.bids, order, true)
+	}
+	for _, order := range diff.Asks {
+		book.asks = upsertLevel(book.asks, order, false)
+	}
+}
+
+func searchLevel(levels []BoardOrder, price Decimal, descending bool) int {
+	if descending {
+		return sort.Search(len(levels), func(i int) bool { return levels[i].Price.Cmp(price) <= 0 })
+	}
+	return sort.Search(len(levels), func(i int) bool { return price.Cmp(levels[i].Price) <= 0 })
+}
+
+func upsertLevel(levels []BoardOrder, order BoardOrder, descending bool) []BoardOrder {
+	i := searchLevel(levels, order.Price, descending)
+	found := i < len(levels) && levels[i].Price == order.Price
+	switch {
+	case found && order.Size.Sign() <= 0:
+		return append(levels[:i], levels[i+1:]...)
+	case found:
+		levels[i].Size = order.Size
+		return levels
+	case order.Size.Sign() <= 0:
+		return levels
+	}
+	levels = append(levels, BoardOrder{})
+	copy(levels[i+1:], levels[i:])
+	levels[i] = order
+	return levels
+}
+
+func (book *OrderBook) MidPrice() Decimal {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	return book.midPrice
+}
+
+func (book *OrderBook) BestBid() (BoardOrder, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	if len(book.bids) == 0 {
+		return BoardOrder{}, false
+	}
+	return book.bids[0], true
+}
+
+func (book *OrderBook) BestAsk() (BoardOrder, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	if len(book.asks) == 0 {
+		return BoardOrder{}, false
+	}
+	return book.asks[0], true
+}
+
+func (book *OrderBook) Spread() (Decimal, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	if len(book.bids) == 0 || len(book.asks) == 0 {
+		return Decimal{}, false
+	}
+	return book.asks[0].Price.Sub(book.bids[0].Price), true
+}
+
+/* DepthAt returns the size resting at price on either side, 0 if there is none. */
+func (book *OrderBook) DepthAt(price Decimal) Decimal {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	if i := searchLevel(book.bids, price, true); i < len(book.bids) && book.bids[i].Price == price {
+		return book.bids[i].Size
+	}
+	if i := searchLevel(book.asks, price, false); i < len(book.asks) && book.asks[i].Price == price {
+		return book.asks[i].Size
+	}
+	return Decimal{}
+}
+
+/*
+ * VWAP returns the average price to fill size by a market order of side:
+ * BUY walks the asks, SELL walks the bids.
+ */
+func (book *OrderBook) VWAP(side string, size Decimal) (Decimal, error) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+
+	var levels []BoardOrder
+	switch side {
+	case BUY:
+		levels = book.asks
+	case SELL:
+		levels = book.bids
+	default:
+		return Decimal{}, errors.New("bitflyer: invalid side: " + side)
+	}
+	if size.Sign() <= 0 {
+		return Decimal{}, errors.New("bitflyer: size must be positive")
+	}
+
+	remaining := size
+	var value Decimal
+	for _, level := range levels {
+		fill := level.Size
+		if remaining.LessThan(fill) {
+			fill = remaining
+		}
+		value = value.Add(level.Price.Mul(fill))
+		remaining = remaining.Sub(fill)
+		if remaining.Sign() <= 0 {
+			return value.Div(size), nil
+		}
+	}
+	return Decimal{}, ErrInsufficientDepth
+}
+
+/* Snapshot returns a copy of the current board. */
+func (book *OrderBook) Snapshot() *GetBoardResponse {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	return &GetBoardResponse{
+		Mid_price: book.midPrice,
+		Bids:      append([]BoardOrder(nil), book.bids...),
+		Asks:      append([]BoardOrder(nil), book.asks...),
+	}
+}
+
+/* GetOrderBook seeds a new OrderBook from GetBoard. */
+func (client *Client) GetOrderBook() (*OrderBook, error) {
+	return client.GetOrderBookWithContext(context.Background())
+}
+
+func (client *Client) GetOrderBookWithContext(ctx context.Context) (*OrderBook, error) {
+	board, err := client.GetBoardWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	book := NewOrderBook()
+	book.Reset(board)
+	return book, nil
+}
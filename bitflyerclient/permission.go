@@ -0,0 +1,103 @@
+package bitflyerclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+const getPermissionsPath = "/v1/me/getpermissions"
+
+/* --- Get API Key Permissions --- */
+/* GetPermissions returns the private API paths the key may call, e.g. "/v1/me/getbalance". */
+func (client *Client) GetPermissions() ([]string, error) {
+	return client.GetPermissionsWithContext(context.Background())
+}
+
+func (client *Client) GetPermissionsWithContext(ctx context.Context) ([]string, error) {
+	reqParam := requestParam{
+		path:      getPermissionsPath,
+		method:    http.MethodGet,
+		isPrivate: true,
+	}
+
+	respBody, err := client.do(ctx, reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0)
+	if err := json.Unmarshal(*respBody, &result); err != nil {
+		client.log(LevelError, "unmarshal response", Field("path", reqParam.path), Field("error", err))
+	}
+
+	return result, err
+}
+
+/* --- Permission preflight --- */
+/*
+ * WithPermissionPreflight makes every private call check the key's permissions
+ * first and fail with PermissionDeniedError before signing and sending.
+ * The permission list is fetched on the first private call and cached;
+ * clients made by ForProduct share the cache.
+ */
+func WithPermissionPreflight() Option {
+	return func(client *Client) {
+		client.permissions = &permissionCache{}
+	}
+}
+
+type permissionCache struct {
+	mu      sync.Mutex
+	allowed map[string]bool /* nil until loaded */
+}
+
+/* RefreshPermissions reloads the cached permission list, e.g. after the key was changed. */
+func (client *Client) RefreshPermissions(ctx context.Context) error {
+	if client.permissions == nil {
+		return nil
+	}
+	client.permissions.mu.Lock()
+	defer client.permissions.mu.Unlock()
+	return client.loadPermissions(ctx)
+}
+
+/* loadPermissions must be called with permissions.mu held */
+func (client *Client) loadPermissions(ctx context.Context) error {
+	paths, err := client.GetPermissionsWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	allowed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		allowed[path] = true
+	}
+	client.permissions.allowed = allowed
+	return nil
+}
+
+/*
+ * checkPermission returns PermissionDeniedError if the key may not call param.
+ * When the permission list can't be loaded the call goes through, and the
+ * load is retried on the next call.
+ */
+func (client *Client) checkPermission(ctx context.Context, param requestParam) error {
+	if client.permissions == nil || !param.isPrivate || param.path == getPermissionsPath {
+		return nil
+	}
+	client.permissions.mu.Lock()
+	defer client.permissions.mu.Unlock()
+	if client.permissions.allowed == nil {
+		if err := client.loadPermissions(ctx); err != nil {
+			client.log(LevelWarn, "load permissions", Field("path", param.path), Field("error", err))
+			return nil
+		}
+	}
+	if !client.permissions.allowed[param.path] {
+		err := &PermissionDeniedError{Path: param.path}
+		client.log(LevelError, "permission denied", Field("method", param.method), Field("path", param.path))
+		return err
+	}
+	return nil
+}
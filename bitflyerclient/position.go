@@ -0,0 +1,61 @@
+package bitflyerclient
+
+import (
+	"context"
+)
+
+/*
+ * NetPosition is the sum of all open positions of a product.
+ * Size is signed: positive for long (BUY), negative for short (SELL).
+ */
+type NetPosition struct {
+	Product_code  string
+	Size          Decimal
+	Average_price Decimal
+	Pnl           Decimal
+}
+
+func (position NetPosition) Side() string {
+	switch position.Size.Sign() {
+	case 1:
+		return BUY
+	case -1:
+		return SELL
+	}
+	return ""
+}
+
+func NetPositions(positions []GetPositionsResponse) NetPosition {
+	var net NetPosition
+	var totalSize, totalValue Decimal
+	for _, position := range positions {
+		net.Product_code = position.Product_code
+		switch position.Side {
+		case BUY:
+			net.Size = net.Size.Add(position.Size)
+		case SELL:
+			net.Size = net.Size.Sub(position.Size)
+		}
+		net.Pnl = net.Pnl.Add(position.Pnl)
+		totalSize = totalSize.Add(position.Size)
+		totalValue = totalValue.Add(position.Price.Mul(position.Size))
+	}
+	if !totalSize.IsZero() {
+		net.Average_price = totalValue.Div(totalSize)
+	}
+	return net
+}
+
+func (client *Client) GetNetPosition() (*NetPosition, error) {
+	return client.GetNetPositionWithContext(context.Background())
+}
+
+func (client *Client) GetNetPositionWithContext(ctx context.Context) (*NetPosition, error) {
+	positions, err := client.GetPositionsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	net := NetPositions(positions)
+	net.Product_code = client.productCode
+	return &net, nil
+}
@@ -0,0 +1,109 @@
+package bitflyerclient
+
+import (
+	"sync"
+)
+
+/* ProductInfo holds the order constraints of a product. */
+type ProductInfo struct {
+	Product_code string
+	Tick_size    Decimal /* price step */
+	Min_size     Decimal
+	Size_step    Decimal
+}
+
+var (
+	productsMu sync.RWMutex
+	products   = map[string]ProductInfo{}
+)
+
+func init() {
+	satoshi := MustParseDecimal("0.00000001")
+	for _, info := range []ProductInfo{
+		{BTC_JPY, DecimalFromInt(1), MustParseDecimal("0.001"), satoshi},
+		{FX_BTC_JPY, DecimalFromInt(1), MustParseDecimal("0.01"), satoshi},
+		{ETH_JPY, DecimalFromInt(1), MustParseDecimal("0.01"), satoshi},
+		{ETH_BTC, MustParseDecimal("0.00001"), MustParseDecimal("0.01"), satoshi},
+		{BCH_BTC, MustParseDecimal("0.00001"), MustParseDecimal("0.01"), satoshi},
+		{BTCJPY_MAT1WK, DecimalFromInt(1), MustParseDecimal("0.01"), satoshi},
+		{BTCJPY_MAT2WK, DecimalFromInt(1), MustParseDecimal("0.01"), satoshi},
+		{BTCJPY_MAT3M, DecimalFromInt(1), MustParseDecimal("0.01"), satoshi},
+	} {
+		products[info.Product_code] = info
+	}
+}
+
+/* RegisterProduct adds or overrides the metadata of a product, e.g. when bitFlyer changes a tick size. */
+func RegisterProduct(info ProductInfo) {
+	productsMu.Lock()
+	defer productsMu.Unlock()
+	products[info.Product_code] = info
+}
+
+func LookupProduct(productCode string) (ProductInfo, bool) {
+	productsMu.RLock()
+	defer productsMu.RUnlock()
+	info, ok := products[productCode]
+	return info, ok
+}
+
+/*
+ * RoundPrice rounds price onto the product's tick: down for BUY and up for
+ * SELL, so that the rounded price is never worse than the requested one.
+ * Unknown products are returned unchanged.
+ */
+func RoundPrice(productCode, side string, price Decimal) Decimal {
+	info, ok := LookupProduct(productCode)
+	if !ok || info.Tick_size.Sign() <= 0 {
+		return price
+	}
+	if side == SELL {
+		return price.RoundUp(info.Tick_size)
+	}
+	return price.RoundDown(info.Tick_size)
+}
+
+/* RoundSize rounds size down onto the product's size step. */
+func RoundSize(productCode string, size Decimal) Decimal {
+	info, ok := LookupProduct(productCode)
+	if !ok || info.Size_step.Sign() <= 0 {
+		return size
+	}
+	return size.RoundDown(info.Size_step)
+}
+
+/* RoundOffset rounds a trail offset down onto the product's tick, an offset has no side. */
+func RoundOffset(productCode string, offset Decimal) Decimal {
+	info, ok := LookupProduct(productCode)
+	if !ok || info.Tick_size.Sign() <= 0 {
+		return offset
+	}
+	return offset.RoundDown(info.Tick_size)
+}
+
+/* WithOrderRounding makes the wrapper APIs round orders with RoundPrice, RoundOffset and RoundSize. */
+func WithOrderRounding(enabled bool) Option {
+	return func(client *Client) {
+		client.roundOrders = enabled
+	}
+}
+
+func (client *Client) roundChildOrder(param *SendChildOrderParam) {
+	if !client.roundOrders {
+		return
+	}
+	productCode := client.product(param.Product_code)
+	param.Price = RoundPrice(productCode, param.Side, param.Price)
+	param.Size = RoundSize(productCode, param.Size)
+}
+
+func (client *Client) roundParentOrder(order *ParentOrder) {
+	if !client.roundOrders {
+		return
+	}
+	productCode := client.product(order.Product_code)
+	order.Price = RoundPrice(productCode, order.Side, order.Price)
+	order.Trigger_price = RoundPrice(productCode, order.Side, order.Trigger_price)
+	order.Offset = RoundOffset(productCode, order.Offset)
+	order.Size = RoundSize(productCode, order.Size)
+}
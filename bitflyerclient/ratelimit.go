@@ -0,0 +1,214 @@
+package bitflyerclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+ * RateLimit configures the client-side limiter.
+ * bitFlyer limits private API calls per key and, separately, order calls
+ * (send/cancel) within the same period. Order calls consume from both budgets.
+ * A limit <= 0 disables the corresponding budget.
+ */
+type RateLimit struct {
+	PrivateLimit int
+	OrderLimit   int
+	Period       time.Duration
+	FailFast     bool /* return ErrRateLimitExceeded instead of waiting */
+}
+
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		PrivateLimit: 500,
+		OrderLimit:   300,
+		Period:       5 * time.Minute,
+	}
+}
+
+var ErrRateLimitExceeded = errors.New("bitflyer: client-side rate limit exceeded")
+
+func WithRateLimit(limit RateLimit) Option {
+	return func(client *Client) {
+		client.rateLimit = limit
+	}
+}
+
+/* --- Token bucket --- */
+type tokenBucket struct {
+	mu           sync.Mutex
+	capacity     float64
+	tokens       float64
+	perSecond    float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func newTokenBucket(limit int, period time.Duration, now time.Time) *tokenBucket {
+	if limit <= 0 || period <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		capacity:  float64(limit),
+		tokens:    float64(limit),
+		perSecond: float64(limit) / period.Seconds(),
+		last:      now,
+	}
+}
+
+func (bucket *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(bucket.last); 0 < elapsed {
+		bucket.tokens += elapsed.Seconds() * bucket.perSecond
+		if bucket.capacity < bucket.tokens {
+			bucket.tokens = bucket.capacity
+		}
+		bucket.last = now
+	}
+}
+
+/* delay returns how long a caller has to wait before a token is available */
+func (bucket *tokenBucket) delay(now time.Time) time.Duration {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.refill(now)
+	var wait time.Duration
+	if now.Before(bucket.blockedUntil) {
+		wait = bucket.blockedUntil.Sub(now)
+	}
+	if bucket.tokens < 1 {
+		if w := time.Duration((1 - bucket.tokens) / bucket.perSecond * float64(time.Second)); wait < w {
+			wait = w
+		}
+	}
+	return wait
+}
+
+func (bucket *tokenBucket) take() {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+	bucket.tokens--
+}
+
+/* refund gives back a token of a call that was never sent */
+func (bucket *tokenBucket) refund() {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+	if bucket.tokens++; bucket.capacity < bucket.tokens {
+		bucket.tokens = bucket.capacity
+	}
+}
+
+/* update trusts the server's view of the remaining budget */
+func (bucket *tokenBucket) update(remaining int, reset time.Time, now time.Time) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.refill(now)
+	if float64(remaining) < bucket.tokens {
+		bucket.tokens = float64(remaining)
+	}
+	if remaining <= 0 && reset.After(now) {
+		bucket.blockedUntil = reset
+	}
+}
+
+/* --- Limiter --- */
+type rateLimiter struct {
+	mu       sync.Mutex /* makes checking and taking tokens of both buckets atomic */
+	private  *tokenBucket
+	order    *tokenBucket
+	failFast bool
+	now      func() time.Time
+}
+
+func newRateLimiter(limit RateLimit, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		private:  newTokenBucket(limit.PrivateLimit, limit.Period, now()),
+		order:    newTokenBucket(limit.OrderLimit, limit.Period, now()),
+		failFast: limit.FailFast,
+		now:      now,
+	}
+}
+
+func (limiter *rateLimiter) buckets(param requestParam) []*tokenBucket {
+	buckets := make([]*tokenBucket, 0, 2)
+	if param.isOrder && limiter.order != nil {
+		buckets = append(buckets, limiter.order)
+	}
+	if param.isPrivate && limiter.private != nil {
+		buckets = append(buckets, limiter.private)
+	}
+	return buckets
+}
+
+/*
+ * wait takes a token from every bucket param consumes, then sleeps until they
+ * are usable. With failFast no token is taken unless all are available now.
+ * Tokens are refunded when ctx is done before the call could be sent.
+ */
+func (limiter *rateLimiter) wait(ctx context.Context, param requestParam) error {
+	buckets := limiter.buckets(param)
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	limiter.mu.Lock()
+	now := limiter.now()
+	var wait time.Duration
+	for _, bucket := range buckets {
+		if w := bucket.delay(now); wait < w {
+			wait = w
+		}
+	}
+	if 0 < wait && limiter.failFast {
+		limiter.mu.Unlock()
+		return ErrRateLimitExceeded
+	}
+	for _, bucket := range buckets {
+		bucket.take()
+	}
+	limiter.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, wait); err != nil {
+		for _, bucket := range buckets {
+			bucket.refund()
+		}
+		return err
+	}
+	return nil
+}
+
+/* observe reads X-RateLimit-* (and X-OrderRequest-RateLimit-*) headers */
+func (limiter *rateLimiter) observe(param requestParam, header http.Header) {
+	now := limiter.now()
+	if param.isPrivate && limiter.private != nil {
+		if remaining, reset, ok := parseRateLimitHeader(header, "X-RateLimit-"); ok {
+			limiter.private.update(remaining, reset, now)
+		}
+	}
+	if param.isOrder && limiter.order != nil {
+		if remaining, reset, ok := parseRateLimitHeader(header, "X-OrderRequest-RateLimit-"); ok {
+			limiter.order.update(remaining, reset, now)
+		}
+	}
+}
+
+func parseRateLimitHeader(header http.Header, prefix string) (int, time.Time, bool) {
+	remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	var reset time.Time
+	if sec, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64); err == nil {
+		reset = time.Unix(sec, 0)
+	}
+	return remaining, reset, true
+}
@@ -0,0 +1,118 @@
+package bitflyerclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	privateParam = requestParam{path: "/v1/me/getbalance", method: http.MethodGet, isPrivate: true}
+	orderParam   = requestParam{path: "/v1/me/sendchildorder", method: http.MethodPost, isPrivate: true, isOrder: true}
+)
+
+func countingHandler(requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func TestRateLimitFailFast(t *testing.T) {
+	var requests int32
+	client := newStubClient(t, countingHandler(&requests),
+		WithRateLimit(RateLimit{PrivateLimit: 1, OrderLimit: 5, Period: time.Hour, FailFast: true}))
+	ctx := context.Background()
+
+	if _, err := client.do(ctx, privateParam); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := client.do(ctx, orderParam); err != ErrRateLimitExceeded {
+			t.Fatalf("do() = %v, want ErrRateLimitExceeded", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("%v requests sent, want 1", requests)
+	}
+	/* Order calls rejected by the private budget must not drain the order budget */
+	if tokens := client.limiter.order.tokens; tokens < 4.99 {
+		t.Errorf("order budget = %v, want 5", tokens)
+	}
+}
+
+func TestRateLimitRefundOnCancel(t *testing.T) {
+	var requests int32
+	client := newStubClient(t, countingHandler(&requests),
+		WithRateLimit(RateLimit{PrivateLimit: 1, OrderLimit: 1, Period: time.Hour}))
+
+	if _, err := client.do(context.Background(), privateParam); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.do(ctx, orderParam); err != context.DeadlineExceeded {
+		t.Fatalf("do() = %v, want DeadlineExceeded", err)
+	}
+	if requests != 1 {
+		t.Errorf("%v requests sent, want 1", requests)
+	}
+	if tokens := client.limiter.private.tokens; tokens < -0.01 {
+		t.Errorf("private budget = %v, the canceled call kept its token", tokens)
+	}
+	if tokens := client.limiter.order.tokens; tokens < 0.99 {
+		t.Errorf("order budget = %v, the canceled call kept its token", tokens)
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	var requests int32
+	reset := time.Now().Add(time.Hour).Unix()
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.Write([]byte(`{}`))
+	}, WithRateLimit(RateLimit{PrivateLimit: 500, Period: 5 * time.Minute, FailFast: true}))
+	ctx := context.Background()
+
+	if _, err := client.do(ctx, privateParam); err != nil {
+		t.Fatal(err)
+	}
+	/* The server says the budget is used up until reset */
+	if _, err := client.do(ctx, privateParam); err != ErrRateLimitExceeded {
+		t.Errorf("do() = %v, want ErrRateLimitExceeded", err)
+	}
+	if !client.limiter.private.blockedUntil.Equal(time.Unix(reset, 0)) {
+		t.Errorf("blockedUntil = %v, want %v", client.limiter.private.blockedUntil, time.Unix(reset, 0))
+	}
+	/* Public calls are not limited */
+	if _, err := client.do(ctx, requestParam{path: "/v1/getticker", method: http.MethodGet}); err != nil {
+		t.Errorf("public do() = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("%v requests sent, want 2", requests)
+	}
+}
+
+func TestRateLimitIgnoresClock(t *testing.T) {
+	var requests int32
+	frozen := time.Unix(1500000000, 0)
+	client := newStubClient(t, countingHandler(&requests),
+		WithClock(func() time.Time { return frozen }),
+		WithRateLimit(RateLimit{PrivateLimit: 2, Period: 100 * time.Millisecond}))
+
+	/* 2 calls right away, then one every 50ms: 200ms, but 500ms if the frozen clock stopped the refill */
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := client.do(context.Background(), privateParam); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); 400*time.Millisecond < elapsed {
+		t.Errorf("6 calls took %v, want about 200ms", elapsed)
+	}
+}
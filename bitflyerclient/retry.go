@@ -0,0 +1,133 @@
+package bitflyerclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+/*
+ * RetryPolicy controls how Client.do retries transient failures:
+ * timeouts, dropped connections, 5xx and 429 responses.
+ * Only GET requests are retried unless RetryNonIdempotent is set, because
+ * retrying a POST such as sendchildorder may place the same order twice.
+ */
+type RetryPolicy struct {
+	MaxAttempts        int /* including the first attempt, 1 disables retries */
+	MinBackoff         time.Duration
+	MaxBackoff         time.Duration /* 0 means no cap */
+	RetryNonIdempotent bool
+	OnAttempt          func(RetryAttempt)
+}
+
+/* RetryAttempt describes one finished attempt and is passed to OnAttempt. */
+type RetryAttempt struct {
+	Method     string
+	Path       string
+	Attempt    int
+	StatusCode int /* 0 when no response was received */
+	Err        error
+	Backoff    time.Duration /* wait before the next attempt, 0 if none follows */
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+	}
+}
+
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(client *Client) {
+		client.retryPolicy = policy
+	}
+}
+
+func (policy *RetryPolicy) allows(param requestParam) bool {
+	return param.method == http.MethodGet || policy.RetryNonIdempotent
+}
+
+/* retryable reports whether err is transient, and the server's Retry-After if any */
+func retryable(ctx context.Context, err error) (bool, time.Duration) {
+	if ctx.Err() != nil || errors.Is(err, ErrRateLimitExceeded) {
+		return false, 0
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusTooManyRequests || http.StatusInternalServerError <= apiErr.StatusCode {
+			return true, parseRetryAfter(apiErr.Header.Get("Retry-After"))
+		}
+		return false, 0
+	}
+	/*
+	 * Every http.Client error is a *url.Error, which implements net.Error, so
+	 * only timeouts and dropped connections are transient. DNS, TLS and
+	 * malformed request errors won't go away by retrying.
+	 */
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true, 0
+	}
+	for _, transient := range transientErrors {
+		if errors.Is(err, transient) {
+			return true, 0
+		}
+	}
+	return false, 0
+}
+
+/* transientErrors are transport errors of a connection dropped by the server or a proxy */
+var transientErrors = []error{io.EOF, io.ErrUnexpectedEOF, syscall.ECONNRESET}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && 0 < seconds {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+/* backoff returns the wait before attempt+1: exponential with jitter */
+func (policy *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	d := policy.MinBackoff
+	for i := 1; i < attempt && (policy.MaxBackoff <= 0 || d < policy.MaxBackoff); i++ {
+		d *= 2
+	}
+	if 0 < policy.MaxBackoff && policy.MaxBackoff < d {
+		d = policy.MaxBackoff
+	}
+	if 0 < d {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+	if d < retryAfter {
+		d = retryAfter
+	}
+	return d
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,156 @@
+package bitflyerclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/* newStubClient returns a client of a server answering every request with handler */
+func newStubClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := New("key", "secret", append([]Option{WithEndpoint(server.URL)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func fastRetryPolicy(attempts *[]RetryAttempt) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  2 * time.Millisecond,
+		OnAttempt:   func(attempt RetryAttempt) { *attempts = append(*attempts, attempt) },
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		policy   func(*RetryPolicy)
+		requests int32
+	}{
+		{"GET 500 is retried", http.MethodGet, http.StatusInternalServerError, nil, 3},
+		{"GET 429 is retried", http.MethodGet, http.StatusTooManyRequests, nil, 3},
+		{"GET 400 is not retried", http.MethodGet, http.StatusBadRequest, nil, 1},
+		{"GET 401 is not retried", http.MethodGet, http.StatusUnauthorized, nil, 1},
+		{"POST is not retried by default", http.MethodPost, http.StatusInternalServerError, nil, 1},
+		{"POST is retried with RetryNonIdempotent", http.MethodPost, http.StatusInternalServerError,
+			func(policy *RetryPolicy) { policy.RetryNonIdempotent = true }, 3},
+		{"NoRetryPolicy", http.MethodGet, http.StatusInternalServerError,
+			func(policy *RetryPolicy) { *policy = NoRetryPolicy() }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			var attempts []RetryAttempt
+			policy := fastRetryPolicy(&attempts)
+			if tt.policy != nil {
+				tt.policy(&policy)
+			}
+			client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tt.status)
+			}, WithRetryPolicy(policy))
+
+			_, err := client.do(context.Background(), requestParam{path: "/v1/test", method: tt.method})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("do() = %v, want APIError %v", err, tt.status)
+			}
+			if requests != tt.requests {
+				t.Errorf("%v requests, want %v", requests, tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var requests int32
+	var attempts []RetryAttempt
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}, WithRetryPolicy(fastRetryPolicy(&attempts)))
+
+	start := time.Now()
+	if _, err := client.do(context.Background(), requestParam{path: "/v1/test", method: http.MethodGet}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want Retry-After of 1s", elapsed)
+	}
+	if len(attempts) != 2 || attempts[0].StatusCode != http.StatusTooManyRequests || attempts[0].Backoff < time.Second {
+		t.Errorf("attempts = %+v", attempts)
+	}
+}
+
+func TestRetryPermanentTransportError(t *testing.T) {
+	var attempts []RetryAttempt
+	client, err := New("key", "secret", WithEndpoint("ftp://127.0.0.1"), WithRetryPolicy(fastRetryPolicy(&attempts)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.do(context.Background(), requestParam{path: "/v1/test", method: http.MethodGet}); err == nil {
+		t.Fatal("do() succeeded")
+	}
+	if len(attempts) != 1 {
+		t.Errorf("%v attempts, want 1", len(attempts))
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&APIError{StatusCode: http.StatusBadGateway, Header: http.Header{}}, true},
+		{&APIError{StatusCode: http.StatusNotFound, Header: http.Header{}}, false},
+		{ErrRateLimitExceeded, false},
+		{context.Canceled, false},
+		{errors.New("tls: bad certificate"), false},
+	}
+	for _, tt := range tests {
+		if got, _ := retryable(context.Background(), tt.err); got != tt.want {
+			t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffGrowth(t *testing.T) {
+	tests := []struct {
+		policy  RetryPolicy
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{RetryPolicy{MinBackoff: 100 * time.Millisecond}, 1, 50 * time.Millisecond, 100 * time.Millisecond},
+		/* No MaxBackoff means no cap */
+		{RetryPolicy{MinBackoff: 100 * time.Millisecond}, 4, 400 * time.Millisecond, 800 * time.Millisecond},
+		{RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}, 4, 150 * time.Millisecond, 300 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := tt.policy.backoff(tt.attempt, 0); d < tt.min || tt.max < d {
+				t.Fatalf("backoff(%v) with %+v = %v, want [%v, %v]", tt.attempt, tt.policy, d, tt.min, tt.max)
+			}
+		}
+	}
+	policy := RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	if d := policy.backoff(1, 2*time.Second); d != 2*time.Second {
+		t.Errorf("backoff with Retry-After = %v", d)
+	}
+}
@@ -0,0 +1,146 @@
+package bitflyerclient
+
+import (
+	"fmt"
+	"time"
+)
+
+var MinOrderSize = MustParseDecimal("0.001")
+
+/* ValidationError is returned by Validate and by the send APIs before anything is sent. */
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("bitflyer: invalid order: %v: %v", e.Field, e.Reason)
+}
+
+func invalid(field, format string, args ...interface{}) error {
+	return &ValidationError{Field: field, Reason: fmt.Sprintf(format, args...)}
+}
+
+func validateSide(field, side string) error {
+	switch side {
+	case BUY, SELL:
+		return nil
+	}
+	return invalid(field, "must be BUY or SELL, got %q", side)
+}
+
+/* validateSize uses the product's Min_size, or MinOrderSize for unknown products */
+func validateSize(field, productCode string, size Decimal) error {
+	minSize := MinOrderSize
+	if info, ok := LookupProduct(productCode); ok {
+		minSize = info.Min_size
+	}
+	if size.LessThan(minSize) {
+		return invalid(field, "must be at least %v for %v, got %v", minSize, productCode, size)
+	}
+	return nil
+}
+
+func validatePositive(field string, value Decimal) error {
+	if value.Sign() <= 0 {
+		return invalid(field, "must be positive, got %v", value)
+	}
+	return nil
+}
+
+func validateExpiry(minuteToExpire uint64, timeInForce TimeInForce) error {
+	if MaxMinuteToExpire < minuteToExpire {
+		return invalid("Minute_to_expire", "must be at most %v, got %v", MaxMinuteToExpire, minuteToExpire)
+	}
+	switch timeInForce {
+	case "", GTC, IOC, FOK:
+		return nil
+	}
+	return invalid("Time_in_force", "must be GTC, IOC or FOK, got %q", timeInForce)
+}
+
+func (param *SendChildOrderParam) Validate() error {
+	return param.validate(time.Now())
+}
+
+/* validate converts an expiry time set by ExpireAt as of now */
+func (param *SendChildOrderParam) validate(now time.Time) error {
+	if err := validateSide("Side", param.Side); err != nil {
+		return err
+	}
+	switch param.Child_order_type {
+	case LIMIT:
+		if err := validatePositive("Price", param.Price); err != nil {
+			return err
+		}
+	case MARKET:
+	default:
+		return invalid("Child_order_type", "must be LIMIT or MARKET, got %q", param.Child_order_type)
+	}
+	if err := validateSize("Size", param.Product_code, param.Size); err != nil {
+		return err
+	}
+	minuteToExpire, err := param.expiry.minutes(param.Minute_to_expire, now)
+	if err != nil {
+		return err
+	}
+	return validateExpiry(minuteToExpire, param.Time_in_force)
+}
+
+/* validate checks a single leg of a parent order, field is used as the error prefix */
+func (order *ParentOrder) validate(field string) error {
+	if err := validateSide(field+".Side", order.Side); err != nil {
+		return err
+	}
+	if err := validateSize(field+".Size", order.Product_code, order.Size); err != nil {
+		return err
+	}
+	switch order.Condition_type {
+	case LIMIT:
+		return validatePositive(field+".Price", order.Price)
+	case MARKET:
+		return nil
+	case STOP:
+		return validatePositive(field+".Trigger_price", order.Trigger_price)
+	case STOP_LIMIT:
+		if err := validatePositive(field+".Price", order.Price); err != nil {
+			return err
+		}
+		return validatePositive(field+".Trigger_price", order.Trigger_price)
+	case TRAIL:
+		return validatePositive(field+".Offset", order.Offset)
+	}
+	return invalid(field+".Condition_type", "unknown condition type %q", order.Condition_type)
+}
+
+func (param *SendParentOrderParam) Validate() error {
+	return param.validate(time.Now())
+}
+
+/* validate converts an expiry time set by ExpireAt as of now */
+func (param *SendParentOrderParam) validate(now time.Time) error {
+	var legs int
+	switch param.Order_method {
+	case SIMPLE:
+		legs = 1
+	case IFD, OCO:
+		legs = 2
+	case IFDOCO:
+		legs = 3
+	default:
+		return invalid("Order_method", "unknown order method %q", param.Order_method)
+	}
+	if len(param.Parameters) != legs {
+		return invalid("Parameters", "%v requires %v orders, got %v", param.Order_method, legs, len(param.Parameters))
+	}
+	for i := range param.Parameters {
+		if err := param.Parameters[i].validate(fmt.Sprintf("Parameters[%d]", i)); err != nil {
+			return err
+		}
+	}
+	minuteToExpire, err := param.expiry.minutes(param.Minute_to_expire, now)
+	if err != nil {
+		return err
+	}
+	return validateExpiry(minuteToExpire, param.Time_in_force)
+}
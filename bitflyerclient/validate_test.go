@@ -0,0 +1,151 @@
+package bitflyerclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	testPrice = DecimalFromInt(1000000)
+	testSize  = MustParseDecimal("0.01")
+)
+
+/* validationField returns the Field of a ValidationError, "" for nil and "<other>" for other errors */
+func validationField(err error) string {
+	if err == nil {
+		return ""
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return "<other>"
+	}
+	return validationErr.Field
+}
+
+func TestSendChildOrderParamValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SendChildOrderParam)
+		field  string
+	}{
+		{"valid limit", func(p *SendChildOrderParam) {}, ""},
+		{"valid market", func(p *SendChildOrderParam) { p.Child_order_type = MARKET; p.Price = Decimal{} }, ""},
+		{"limit with price 0", func(p *SendChildOrderParam) { p.Price = Decimal{} }, "Price"},
+		{"limit with negative price", func(p *SendChildOrderParam) { p.Price = DecimalFromInt(-1) }, "Price"},
+		{"unknown type", func(p *SendChildOrderParam) { p.Child_order_type = STOP }, "Child_order_type"},
+		{"unknown side", func(p *SendChildOrderParam) { p.Side = "buy" }, "Side"},
+		{"size below product minimum", func(p *SendChildOrderParam) { p.Size = MustParseDecimal("0.009") }, "Size"},
+		{"size at product minimum", func(p *SendChildOrderParam) { p.Size = MustParseDecimal("0.01") }, ""},
+		{"BTC_JPY allows smaller sizes", func(p *SendChildOrderParam) {
+			p.Product_code = BTC_JPY
+			p.Size = MustParseDecimal("0.001")
+		}, ""},
+		{"unknown product uses MinOrderSize", func(p *SendChildOrderParam) {
+			p.Product_code = "UNKNOWN"
+			p.Size = MustParseDecimal("0.0009")
+		}, "Size"},
+		{"expiry above 43200", func(p *SendChildOrderParam) { p.Minute_to_expire = MaxMinuteToExpire + 1 }, "Minute_to_expire"},
+		{"expiry at 43200", func(p *SendChildOrderParam) { p.Minute_to_expire = MaxMinuteToExpire }, ""},
+		{"unknown time in force", func(p *SendChildOrderParam) { p.Time_in_force = "GTD" }, "Time_in_force"},
+	}
+	for _, tt := range tests {
+		param := NewSendChildOrderParam()
+		param.Product_code = FX_BTC_JPY
+		param.Child_order_type = LIMIT
+		param.Side = BUY
+		param.Price = testPrice
+		param.Size = testSize
+		tt.modify(param)
+		if field := validationField(param.Validate()); field != tt.field {
+			t.Errorf("%v: Validate() failed on %q, want %q", tt.name, field, tt.field)
+		}
+	}
+}
+
+func TestSendParentOrderParamValidate(t *testing.T) {
+	limit := LimitLeg(BUY, testPrice, testSize)
+	tests := []struct {
+		name   string
+		method string
+		legs   []ParentOrder
+		expiry uint64
+		field  string
+	}{
+		{"simple", SIMPLE, []ParentOrder{limit}, 0, ""},
+		{"IFD", IFD, []ParentOrder{limit, LimitLeg(SELL, testPrice, testSize)}, 0, ""},
+		{"IFDOCO", IFDOCO, []ParentOrder{limit, LimitLeg(SELL, testPrice, testSize), StopLeg(SELL, testPrice, testSize)}, 0, ""},
+		{"IFDOCO with 2 legs", IFDOCO, []ParentOrder{limit, limit}, 0, "Parameters"},
+		{"OCO with 3 legs", OCO, []ParentOrder{limit, limit, limit}, 0, "Parameters"},
+		{"simple without legs", SIMPLE, []ParentOrder{}, 0, "Parameters"},
+		{"unknown method", "IFO", []ParentOrder{limit}, 0, "Order_method"},
+		{"STOP without trigger", SIMPLE, []ParentOrder{StopLeg(SELL, Decimal{}, testSize)}, 0, "Parameters[0].Trigger_price"},
+		{"STOP_LIMIT without price", SIMPLE, []ParentOrder{StopLimitLeg(SELL, Decimal{}, testPrice, testSize)}, 0, "Parameters[0].Price"},
+		{"STOP_LIMIT without trigger", SIMPLE, []ParentOrder{StopLimitLeg(SELL, testPrice, Decimal{}, testSize)}, 0, "Parameters[0].Trigger_price"},
+		{"TRAIL without offset", SIMPLE, []ParentOrder{TrailLeg(SELL, Decimal{}, testSize)}, 0, "Parameters[0].Offset"},
+		{"LIMIT leg with price 0", IFD, []ParentOrder{limit, LimitLeg(SELL, Decimal{}, testSize)}, 0, "Parameters[1].Price"},
+		{"leg below product minimum", SIMPLE, []ParentOrder{LimitLeg(BUY, testPrice, MustParseDecimal("0.005"))}, 0, "Parameters[0].Size"},
+		{"leg with unknown side", SIMPLE, []ParentOrder{LimitLeg("", testPrice, testSize)}, 0, "Parameters[0].Side"},
+		{"leg with unknown condition", SIMPLE, []ParentOrder{{Condition_type: "IOC", Side: BUY, Size: testSize}}, 0, "Parameters[0].Condition_type"},
+		{"expiry above 43200", SIMPLE, []ParentOrder{limit}, MaxMinuteToExpire + 1, "Minute_to_expire"},
+	}
+	for _, tt := range tests {
+		param := NewSendParentOrderParam()
+		param.Order_method = tt.method
+		if tt.expiry != 0 {
+			param.Minute_to_expire = tt.expiry
+		}
+		for _, leg := range tt.legs {
+			leg.Product_code = FX_BTC_JPY
+			param.Parameters = append(param.Parameters, leg)
+		}
+		if field := validationField(param.Validate()); field != tt.field {
+			t.Errorf("%v: Validate() failed on %q, want %q", tt.name, field, tt.field)
+		}
+	}
+}
+
+func TestParentOrderBuilder(t *testing.T) {
+	entry := LimitLeg(BUY, testPrice, testSize)
+	exit := LimitLeg(SELL, testPrice, testSize)
+	stop := StopLeg(SELL, testPrice, testSize)
+	tests := []struct {
+		name   string
+		build  func() *ParentOrderBuilder
+		method string
+		field  string
+	}{
+		{"simple", func() *ParentOrderBuilder { return NewParentOrder().Order(entry) }, SIMPLE, ""},
+		{"OCO", func() *ParentOrderBuilder { return NewParentOrder().OCO(exit, stop) }, OCO, ""},
+		{"IFD", func() *ParentOrderBuilder { return NewParentOrder().If(entry).Then().Order(exit) }, IFD, ""},
+		{"IFDOCO", func() *ParentOrderBuilder {
+			return NewParentOrder().IfLimit(BUY, testPrice, testSize).Then().OCO(exit, stop)
+		}, IFDOCO, ""},
+		{"no order", func() *ParentOrderBuilder { return NewParentOrder() }, "", "Parameters"},
+		{"If without Then", func() *ParentOrderBuilder { return NewParentOrder().If(entry).Order(exit) }, "", "Parameters"},
+		{"Then without If", func() *ParentOrderBuilder { return NewParentOrder().Then().Order(exit) }, "", "Parameters"},
+		{"If after Order", func() *ParentOrderBuilder { return NewParentOrder().Order(exit).If(entry) }, "", "Parameters"},
+		{"two If", func() *ParentOrderBuilder { return NewParentOrder().If(entry).If(entry).Then().Order(exit) }, "", "Parameters"},
+		{"Order after OCO", func() *ParentOrderBuilder { return NewParentOrder().OCO(exit, stop).Order(exit) }, "", "Parameters"},
+		{"two Order", func() *ParentOrderBuilder { return NewParentOrder().Order(entry).Order(exit) }, "", "Parameters"},
+		{"invalid leg", func() *ParentOrderBuilder {
+			return NewParentOrder().If(entry).Then().OCO(exit, StopLeg(SELL, Decimal{}, testSize))
+		}, "", "Parameters[2].Trigger_price"},
+		{"invalid expiry", func() *ParentOrderBuilder {
+			return NewParentOrder().Order(entry).ExpireAfter(31 * 24 * time.Hour)
+		}, "", "Minute_to_expire"},
+		{"invalid time in force", func() *ParentOrderBuilder {
+			return NewParentOrder().Order(entry).TimeInForce("GTD")
+		}, "", "Time_in_force"},
+	}
+	for _, tt := range tests {
+		param, err := tt.build().Build()
+		if field := validationField(err); field != tt.field {
+			t.Errorf("%v: Build() failed on %q (%v), want %q", tt.name, field, err, tt.field)
+			continue
+		}
+		if err == nil && param.Order_method != tt.method {
+			t.Errorf("%v: Order_method = %v, want %v", tt.name, param.Order_method, tt.method)
+		}
+	}
+}
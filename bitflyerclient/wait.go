@@ -0,0 +1,288 @@
+package bitflyerclient
+
+import (
+	"context"
+	"time"
+)
+
+var terminalOrderStates = []string{COMPLETED, CANCELED, EXPIRED, REJECTED}
+
+/* Event types (realtime.EVENT_*) after which an order can be in a terminal state */
+var terminalOrderEvents = []string{"COMPLETE", "CANCEL", "EXPIRE", "ORDER_FAILED"}
+
+/*
+ * OrderEvent notifies that something happened to an order.
+ * It only wakes up a waiter, the order itself is always re-read from the REST API.
+ * Outstanding_size is set for child order EXECUTION events.
+ */
+type OrderEvent struct {
+	Child_order_acceptance_id  string
+	Parent_order_acceptance_id string
+	Event_type                 string
+	Outstanding_size           Decimal
+}
+
+/* terminal reports whether the order may have reached a terminal state, a partial fill does not */
+func (event OrderEvent) terminal() bool {
+	if event.Event_type == "EXECUTION" {
+		return event.Outstanding_size.IsZero()
+	}
+	return containsState(terminalOrderEvents, event.Event_type)
+}
+
+/* OrderEventSource is implemented by realtime.OrderEventBroker. */
+type OrderEventSource interface {
+	SubscribeOrderEvents() (events <-chan OrderEvent, unsubscribe func())
+}
+
+/* --- Wait options --- */
+type waitConfig struct {
+	pollInterval    time.Duration
+	events          OrderEventSource
+	productCode     string
+	notFoundTimeout time.Duration
+}
+
+type WaitOption func(*waitConfig)
+
+/*
+ * WithPollInterval sets the polling interval, 5 seconds by default (30 seconds with WithOrderEvents).
+ * Every poll is one private API call and counts against the client's rate limit.
+ */
+func WithPollInterval(interval time.Duration) WaitOption {
+	return func(config *waitConfig) {
+		config.pollInterval = interval
+	}
+}
+
+/*
+ * WithOrderEvents re-reads the order on its events instead of polling frequently.
+ * Partial fills and other non-terminal events are ignored unless until lists a non-terminal state.
+ */
+func WithOrderEvents(events OrderEventSource) WaitOption {
+	return func(config *waitConfig) {
+		config.events = events
+	}
+}
+
+/* WithOrderProduct sets the product the order was placed on, the client default otherwise. */
+func WithOrderProduct(productCode string) WaitOption {
+	return func(config *waitConfig) {
+		config.productCode = productCode
+	}
+}
+
+/*
+ * WithNotFoundTimeout sets how long an order may stay unlisted before the wait
+ * fails with OrderNotFoundError, 2 minutes by default. A negative timeout waits
+ * until ctx is done.
+ */
+func WithNotFoundTimeout(timeout time.Duration) WaitOption {
+	return func(config *waitConfig) {
+		config.notFoundTimeout = timeout
+	}
+}
+
+func newWaitConfig(opts []WaitOption) *waitConfig {
+	config := &waitConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.pollInterval <= 0 {
+		config.pollInterval = 5 * time.Second
+		if config.events != nil {
+			config.pollInterval = 30 * time.Second
+		}
+	}
+	if config.notFoundTimeout == 0 {
+		config.notFoundTimeout = 2 * time.Minute
+	}
+	return config
+}
+
+/* notFound returns OrderNotFoundError once an order unlisted since start is past the timeout */
+func (config *waitConfig) notFound(start time.Time, acceptanceID string) error {
+	if config.notFoundTimeout < 0 || time.Since(start) < config.notFoundTimeout {
+		return nil
+	}
+	return &OrderNotFoundError{Acceptance_id: acceptanceID}
+}
+
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+/* wakeOn returns the events worth a re-read, only terminal ones unless until has other states */
+func wakeOn(until []string) func(OrderEvent) bool {
+	for _, state := range until {
+		if !containsState(terminalOrderStates, state) {
+			return func(OrderEvent) bool { return true }
+		}
+	}
+	return OrderEvent.terminal
+}
+
+/*
+ * waitFor calls check until it reports done, sleeping between calls until
+ * the poll interval elapses or an event matching the order arrives. Events
+ * queued up meanwhile are dropped, one re-read covers all of them.
+ */
+func waitFor(ctx context.Context, config *waitConfig, match func(OrderEvent) bool, check func() (bool, error)) error {
+	var events <-chan OrderEvent
+	if config.events != nil {
+		var unsubscribe func()
+		events, unsubscribe = config.events.SubscribeOrderEvents()
+		defer unsubscribe()
+	}
+	ticker := time.NewTicker(config.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+
+	sleep:
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				break sleep
+			case event, ok := <-events:
+				if !ok {
+					/* The event source is gone, keep polling */
+					events = nil
+					continue
+				}
+				if match(event) {
+					break sleep
+				}
+			}
+		}
+		events = drainEvents(events)
+	}
+}
+
+/* drainEvents discards queued events, it returns nil once events is closed */
+func drainEvents(events <-chan OrderEvent) <-chan OrderEvent {
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+		default:
+			return events
+		}
+	}
+}
+
+/*
+ * WaitForChildOrder waits until the child order reaches one of the until
+ * states (COMPLETED, CANCELED, EXPIRED or REJECTED if until is empty) and
+ * returns the order. Use WithOrderProduct for an order of another product
+ * than the client default.
+ */
+func (client *Client) WaitForChildOrder(ctx context.Context, acceptanceID string, until []string, opts ...WaitOption) (*GetChildOrdersResponse, error) {
+	if len(until) == 0 {
+		until = terminalOrderStates
+	}
+	config := newWaitConfig(opts)
+	param := NewGetChildOrdersParam()
+	param.Product_code = config.productCode
+	param.Child_order_acceptance_id = acceptanceID
+
+	start := time.Now()
+	listed := false
+	var result *GetChildOrdersResponse
+	wake := wakeOn(until)
+	match := func(event OrderEvent) bool {
+		return event.Child_order_acceptance_id == acceptanceID && wake(event)
+	}
+	err := waitFor(ctx, config, match, func() (bool, error) {
+		orders, err := client.GetChildOrdersWithContext(ctx, param)
+		if err != nil {
+			return false, err
+		}
+		for i := range orders {
+			if orders[i].Child_order_acceptance_id != acceptanceID {
+				continue
+			}
+			listed = true
+			if containsState(until, orders[i].Child_order_state) {
+				result = &orders[i]
+				return true, nil
+			}
+		}
+		/* The order may not be listed yet right after it was accepted */
+		if !listed {
+			return false, config.notFound(start, acceptanceID)
+		}
+		return false, nil
+	})
+	return result, err
+}
+
+/*
+ * WaitForParentOrder waits until the parent order reaches one of the until
+ * states (COMPLETED, CANCELED, EXPIRED or REJECTED if until is empty) and
+ * returns the order. Once the order is found, every poll is a single call.
+ */
+func (client *Client) WaitForParentOrder(ctx context.Context, acceptanceID string, until []string, opts ...WaitOption) (*GetParentOrdersResponse, error) {
+	if len(until) == 0 {
+		until = terminalOrderStates
+	}
+	config := newWaitConfig(opts)
+
+	/* The order was sent before waiting, allow for clock skew to the exchange */
+	start := time.Now()
+	since := start.Add(-10 * time.Minute)
+
+	var listed *GetParentOrdersResponse
+	var result *GetParentOrdersResponse
+	wake := wakeOn(until)
+	match := func(event OrderEvent) bool {
+		return event.Parent_order_acceptance_id == acceptanceID && wake(event)
+	}
+	err := waitFor(ctx, config, match, func() (bool, error) {
+		var order *GetParentOrdersResponse
+		var err error
+		if listed == nil {
+			order, err = client.findParentOrder(ctx, acceptanceID, config.productCode, since)
+		} else {
+			order, err = client.getParentOrderById(ctx, listed.Product_code, listed.Id, acceptanceID)
+		}
+		if err != nil {
+			return false, err
+		}
+		if order == nil {
+			if listed == nil {
+				return false, config.notFound(start, acceptanceID)
+			}
+			return false, nil
+		}
+		listed = order
+		if containsState(until, order.Parent_order_state) {
+			result = order
+			return true, nil
+		}
+		return false, nil
+	})
+	return result, err
+}
+
+/* findParentOrder returns nil if the order is not listed yet */
+func (client *Client) findParentOrder(ctx context.Context, acceptanceID, productCode string, since time.Time) (*GetParentOrdersResponse, error) {
+	order, err := client.GetParentOrderByAcceptanceIdWithContext(ctx, acceptanceID, productCode, since)
+	if IsOrderNotFound(err) {
+		return nil, nil
+	}
+	return order, err
+}
@@ -0,0 +1,70 @@
+package bitflyerclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/* fakeOrderEvents hands out a channel holding events, closed like a finished broker */
+type fakeOrderEvents struct {
+	events []OrderEvent
+}
+
+func (source *fakeOrderEvents) SubscribeOrderEvents() (<-chan OrderEvent, func()) {
+	ch := make(chan OrderEvent, len(source.events))
+	for _, event := range source.events {
+		ch <- event
+	}
+	return ch, func() {}
+}
+
+func TestWaitForChildOrderEvents(t *testing.T) {
+	const id = "JRF20200101-000000-000001"
+	partial := OrderEvent{Child_order_acceptance_id: id, Event_type: "EXECUTION", Outstanding_size: testSize}
+	filled := OrderEvent{Child_order_acceptance_id: id, Event_type: "EXECUTION"}
+	cancel := OrderEvent{Child_order_acceptance_id: id, Event_type: "CANCEL"}
+	other := OrderEvent{Child_order_acceptance_id: "JRF20200101-000000-000002", Event_type: "CANCEL"}
+
+	repeat := func(event OrderEvent, n int) []OrderEvent {
+		events := make([]OrderEvent, n)
+		for i := range events {
+			events[i] = event
+		}
+		return events
+	}
+	tests := []struct {
+		name   string
+		events []OrderEvent
+		until  []string
+		calls  int32
+	}{
+		{"partial fills are ignored", repeat(partial, 16), nil, 1},
+		{"events of other orders are ignored", repeat(other, 4), nil, 1},
+		{"queued terminal events cause one re-read", append(repeat(partial, 16), cancel, cancel, cancel), nil, 2},
+		{"the last fill wakes up", []OrderEvent{partial, filled}, nil, 2},
+		{"partial fills wake up for non-terminal states", repeat(partial, 16), []string{COMPLETED, ACTIVE}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Write([]byte(`[{"child_order_acceptance_id":"` + id + `","child_order_state":"PENDING"}]`))
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			_, err := client.WaitForChildOrder(ctx, id, tt.until,
+				WithOrderEvents(&fakeOrderEvents{events: tt.events}), WithPollInterval(time.Hour))
+			if err != context.DeadlineExceeded {
+				t.Errorf("WaitForChildOrder() = %v", err)
+			}
+			if calls := atomic.LoadInt32(&calls); calls != tt.calls {
+				t.Errorf("%v GetChildOrders calls, want %v", calls, tt.calls)
+			}
+		})
+	}
+}
@@ -1,117 +1,367 @@
 package bitflyerclient
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 )
 
+/*
+ * Wrapper APIs take float64 prices and sizes for convenience,
+ * they are rounded to Decimal (8 decimal places) before sending.
+ */
 func (client *Client) SendChildOrderMarket(side string, size float64) (*SendChildOrderResponse, error) {
-	param := NewSendChildOrderParam()
-	param.Child_order_type = MARKET
-	param.Side = side
-	param.Size = size
-	return client.SendChildOrder(param)
+	return client.SendChildOrderMarketWithContext(context.Background(), side, size)
+}
+
+func (client *Client) SendChildOrderMarketWithContext(ctx context.Context, side string, size float64) (*SendChildOrderResponse, error) {
+	return client.sendChildOrder(ctx, MARKET, side, 0, size, GTC)
+}
+
+func (client *Client) SendChildOrderMarketIOC(side string, size float64) (*SendChildOrderResponse, error) {
+	return client.SendChildOrderMarketIOCWithContext(context.Background(), side, size)
+}
+
+func (client *Client) SendChildOrderMarketIOCWithContext(ctx context.Context, side string, size float64) (*SendChildOrderResponse, error) {
+	return client.sendChildOrder(ctx, MARKET, side, 0, size, IOC)
+}
+
+func (client *Client) SendChildOrderMarketFOK(side string, size float64) (*SendChildOrderResponse, error) {
+	return client.SendChildOrderMarketFOKWithContext(context.Background(), side, size)
+}
+
+func (client *Client) SendChildOrderMarketFOKWithContext(ctx context.Context, side string, size float64) (*SendChildOrderResponse, error) {
+	return client.sendChildOrder(ctx, MARKET, side, 0, size, FOK)
 }
 
 func (client *Client) SendChildOrderLimit(side string, price, size float64) (*SendChildOrderResponse, error) {
+	return client.SendChildOrderLimitWithContext(context.Background(), side, price, size)
+}
+
+func (client *Client) SendChildOrderLimitWithContext(ctx context.Context, side string, price, size float64) (*SendChildOrderResponse, error) {
+	return client.sendChildOrder(ctx, LIMIT, side, price, size, GTC)
+}
+
+func (client *Client) SendChildOrderLimitIOC(side string, price, size float64) (*SendChildOrderResponse, error) {
+	return client.SendChildOrderLimitIOCWithContext(context.Background(), side, price, size)
+}
+
+func (client *Client) SendChildOrderLimitIOCWithContext(ctx context.Context, side string, price, size float64) (*SendChildOrderResponse, error) {
+	return client.sendChildOrder(ctx, LIMIT, side, price, size, IOC)
+}
+
+func (client *Client) SendChildOrderLimitFOK(side string, price, size float64) (*SendChildOrderResponse, error) {
+	return client.SendChildOrderLimitFOKWithContext(context.Background(), side, price, size)
+}
+
+func (client *Client) SendChildOrderLimitFOKWithContext(ctx context.Context, side string, price, size float64) (*SendChildOrderResponse, error) {
+	return client.sendChildOrder(ctx, LIMIT, side, price, size, FOK)
+}
+
+func (client *Client) sendChildOrder(ctx context.Context, orderType, side string, price, size float64, timeInForce TimeInForce) (*SendChildOrderResponse, error) {
 	param := NewSendChildOrderParam()
-	param.Child_order_type = LIMIT
+	param.Child_order_type = orderType
 	param.Side = side
-	param.Price = price
-	param.Size = size
-	return client.SendChildOrder(param)
+	param.Price = DecimalFromFloat(price)
+	param.Size = DecimalFromFloat(size)
+	param.Time_in_force = timeInForce
+	if orderType == LIMIT {
+		client.roundChildOrder(param)
+	}
+	return client.SendChildOrderWithContext(ctx, param)
 }
 
 func (client *Client) SendParentOrderStop(side string, price, size float64) (*SendParentOrderResponse, error) {
+	return client.SendParentOrderStopWithContext(context.Background(), side, price, size)
+}
+
+func (client *Client) SendParentOrderStopWithContext(ctx context.Context, side string, price, size float64) (*SendParentOrderResponse, error) {
 	param := NewSendParentOrderParam()
 	param.Order_method = SIMPLE
 	parentOrder := ParentOrder{
 		Condition_type: STOP,
 		Side:           side,
-		Size:           size,
-		Trigger_price:  price,
+		Size:           DecimalFromFloat(size),
+		Trigger_price:  DecimalFromFloat(price),
 	}
+	client.roundParentOrder(&parentOrder)
 	param.Parameters = append(param.Parameters, parentOrder)
-	return client.SendParentOrder(param)
+	return client.SendParentOrderWithContext(ctx, param)
 }
 
 func (client *Client) SendParentOrderIFDOCO(conditionType, side string, entry, limit, stop, size float64) (*SendParentOrderResponse, error) {
+	return client.SendParentOrderIFDOCOWithContext(context.Background(), conditionType, side, entry, limit, stop, size)
+}
+
+func (client *Client) SendParentOrderIFDOCOWithContext(ctx context.Context, conditionType, side string, entry, limit, stop, size float64) (*SendParentOrderResponse, error) {
+	exitSide := oppositeSide(side)
 	param := NewSendParentOrderParam()
 	param.Order_method = IFDOCO
+	param.Parameters = append(param.Parameters,
+		entryLeg(conditionType, side, entry, size),
+		LimitLeg(exitSide, DecimalFromFloat(limit), DecimalFromFloat(size)),
+		StopLeg(exitSide, DecimalFromFloat(stop), DecimalFromFloat(size)))
 
-	var parentOrder ParentOrder
+	for i := range param.Parameters {
+		client.roundParentOrder(&param.Parameters[i])
+	}
+	return client.SendParentOrderWithContext(ctx, param)
+}
 
-	/* entry */
-	parentOrder = ParentOrder{
+/* entryLeg places entry on Price for LIMIT and on Trigger_price for STOP */
+func entryLeg(conditionType, side string, entry, size float64) ParentOrder {
+	parentOrder := ParentOrder{
 		Condition_type: conditionType,
 		Side:           side,
-		Size:           size,
+		Size:           DecimalFromFloat(size),
 	}
 	switch conditionType {
 	case LIMIT:
-		parentOrder.Price = entry
+		parentOrder.Price = DecimalFromFloat(entry)
 	case STOP:
-		parentOrder.Trigger_price = entry
+		parentOrder.Trigger_price = DecimalFromFloat(entry)
 	}
+	return parentOrder
+}
 
+func (client *Client) SendParentOrderTrail(side string, offset, size float64) (*SendParentOrderResponse, error) {
+	return client.SendParentOrderTrailWithContext(context.Background(), side, offset, size)
+}
+
+func (client *Client) SendParentOrderTrailWithContext(ctx context.Context, side string, offset, size float64) (*SendParentOrderResponse, error) {
+	param := NewSendParentOrderParam()
+	param.Order_method = SIMPLE
+	parentOrder := TrailLeg(side, DecimalFromFloat(offset), DecimalFromFloat(size))
+	client.roundParentOrder(&parentOrder)
 	param.Parameters = append(param.Parameters, parentOrder)
+	return client.SendParentOrderWithContext(ctx, param)
+}
 
-	/* limit */
-	var oppositeSide string
-	switch side {
-	case BUY:
-		oppositeSide = SELL
-	case SELL:
-		oppositeSide = BUY
-	}
+/* SendParentOrderIFD enters by a LIMIT or STOP order, then exits by an opposite LIMIT order. */
+func (client *Client) SendParentOrderIFD(conditionType, side string, entry, exit, size float64) (*SendParentOrderResponse, error) {
+	return client.SendParentOrderIFDWithContext(context.Background(), conditionType, side, entry, exit, size)
+}
 
-	parentOrder = ParentOrder{
-		Condition_type: LIMIT,
-		Side:           oppositeSide,
-		Size:           size,
-		Price:          limit,
-	}
-	param.Parameters = append(param.Parameters, parentOrder)
+func (client *Client) SendParentOrderIFDWithContext(ctx context.Context, conditionType, side string, entry, exit, size float64) (*SendParentOrderResponse, error) {
+	param := NewSendParentOrderParam()
+	param.Order_method = IFD
+	param.Parameters = append(param.Parameters,
+		entryLeg(conditionType, side, entry, size),
+		LimitLeg(oppositeSide(side), DecimalFromFloat(exit), DecimalFromFloat(size)))
 
-	/* stop */
-	parentOrder = ParentOrder{
-		Condition_type: STOP,
-		Side:           oppositeSide,
-		Size:           size,
-		Trigger_price:  stop,
+	for i := range param.Parameters {
+		client.roundParentOrder(&param.Parameters[i])
 	}
-	param.Parameters = append(param.Parameters, parentOrder)
+	return client.SendParentOrderWithContext(ctx, param)
+}
 
-	return client.SendParentOrder(param)
+/* SendParentOrderOCO places a LIMIT and a STOP order of the same side, e.g. to close a position. */
+func (client *Client) SendParentOrderOCO(side string, limit, stop, size float64) (*SendParentOrderResponse, error) {
+	return client.SendParentOrderOCOWithContext(context.Background(), side, limit, stop, size)
+}
+
+func (client *Client) SendParentOrderOCOWithContext(ctx context.Context, side string, limit, stop, size float64) (*SendParentOrderResponse, error) {
+	param := NewSendParentOrderParam()
+	param.Order_method = OCO
+	param.Parameters = append(param.Parameters,
+		LimitLeg(side, DecimalFromFloat(limit), DecimalFromFloat(size)),
+		StopLeg(side, DecimalFromFloat(stop), DecimalFromFloat(size)))
+
+	for i := range param.Parameters {
+		client.roundParentOrder(&param.Parameters[i])
+	}
+	return client.SendParentOrderWithContext(ctx, param)
 }
 
 func (client *Client) GetParentOrdersByState(state string) ([]GetParentOrdersResponse, error) {
+	return client.GetParentOrdersByStateWithContext(context.Background(), state)
+}
+
+func (client *Client) GetParentOrdersByStateWithContext(ctx context.Context, state string) ([]GetParentOrdersResponse, error) {
 	param := NewGetParentOrdersParam()
 	param.Parent_order_state = state
-	return client.GetParentOrders(param)
+	return client.GetParentOrdersWithContext(ctx, param)
 }
 
+/*
+ * GetParentOrderState only walks the orders of the last 30 days
+ * (MaxMinuteToExpire) if GetParentOrder can't find the order, use
+ * GetParentOrderByAcceptanceId for older orders.
+ */
 func (client *Client) GetParentOrderState(id string) (string, error) {
-	param := NewGetParentOrdersParam()
-	for _, count := range []int64{100, 400, 1000} {
-		param.Page.Count = count
-		orders, err := client.GetParentOrders(param)
-		if err != nil {
-			return "", err
+	return client.GetParentOrderStateWithContext(context.Background(), id)
+}
+
+func (client *Client) GetParentOrderStateWithContext(ctx context.Context, id string) (string, error) {
+	since := time.Now().Add(-MaxMinuteToExpire * time.Minute)
+	order, err := client.GetParentOrderByAcceptanceIdWithContext(ctx, id, "", since)
+	if err != nil {
+		return "", err
+	}
+	return order.Parent_order_state, nil
+}
+
+/*
+ * GetParentOrderByAcceptanceId looks the order up by its id from GetParentOrder
+ * first, and otherwise walks GetParentOrders of productCode (the client
+ * default if empty) back until the list is exhausted or orders are older
+ * than since (zero since means no bound).
+ * It returns OrderNotFoundError if the order is not listed.
+ */
+func (client *Client) GetParentOrderByAcceptanceId(id, productCode string, since time.Time) (*GetParentOrdersResponse, error) {
+	return client.GetParentOrderByAcceptanceIdWithContext(context.Background(), id, productCode, since)
+}
+
+func (client *Client) GetParentOrderByAcceptanceIdWithContext(ctx context.Context, id, productCode string, since time.Time) (*GetParentOrdersResponse, error) {
+	detailParam := NewGetParentOrderParam()
+	detailParam.Parent_order_acceptance_id = id
+	detail, err := client.GetParentOrderWithContext(ctx, detailParam)
+	switch {
+	case err == nil && 0 < detail.Id:
+		/* The order may have been placed on another product than the client default */
+		if 0 < len(detail.Parameters) && detail.Parameters[0].Product_code != "" {
+			productCode = detail.Parameters[0].Product_code
 		}
-		for _, order := range orders {
-			if order.Parent_order_acceptance_id == id {
-				return order.Parent_order_state, nil
-			}
+		order, err := client.getParentOrderById(ctx, productCode, detail.Id, id)
+		if err != nil || order != nil {
+			return order, err
 		}
-		param.Page.Before = orders[len(orders)-1].Id
+	case err != nil && !isClientError(err):
+		return nil, err
 	}
 
-	return "", fmt.Errorf("not found parent id: %v", id)
+	param := NewGetParentOrdersParam()
+	param.Product_code = productCode
+	it := client.IterParentOrders(ctx, param).Since(since)
+	for it.Next() {
+		if order := it.Value(); order.Parent_order_acceptance_id == id {
+			return &order, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, &OrderNotFoundError{Acceptance_id: id}
+}
+
+/* getParentOrderById lists the order with id (as of GetParentOrder) in one call, nil if it is not listed */
+func (client *Client) getParentOrderById(ctx context.Context, productCode string, id int64, acceptanceID string) (*GetParentOrdersResponse, error) {
+	/* "before" is exclusive, so before=id+1 lists the order itself first */
+	param := NewGetParentOrdersParam()
+	param.Product_code = productCode
+	param.Page.Count = 1
+	param.Page.Before = id + 1
+	orders, err := client.GetParentOrdersWithContext(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	if 0 < len(orders) && orders[0].Parent_order_acceptance_id == acceptanceID {
+		return &orders[0], nil
+	}
+	return nil, nil
 }
 
 func (client *Client) GetChildOrdersByChildOrderId(id string) ([]GetChildOrdersResponse, error) {
+	return client.GetChildOrdersByChildOrderIdWithContext(context.Background(), id)
+}
+
+func (client *Client) GetChildOrdersByChildOrderIdWithContext(ctx context.Context, id string) ([]GetChildOrdersResponse, error) {
 	param := NewGetChildOrdersParam()
-    param.Child_order_id = id
-	return client.GetChildOrders(param)
+	param.Child_order_id = id
+	return client.GetChildOrdersWithContext(ctx, param)
 }
 
+func (client *Client) CancelChildOrderById(id string) error {
+	return client.CancelChildOrderByIdWithContext(context.Background(), id)
+}
+
+func (client *Client) CancelChildOrderByIdWithContext(ctx context.Context, id string) error {
+	param := NewCancelChildOrderParam()
+	param.Child_order_id = id
+	return client.CancelChildOrderWithContext(ctx, param)
+}
+
+func (client *Client) CancelChildOrderByAcceptanceId(id string) error {
+	return client.CancelChildOrderByAcceptanceIdWithContext(context.Background(), id)
+}
+
+func (client *Client) CancelChildOrderByAcceptanceIdWithContext(ctx context.Context, id string) error {
+	param := NewCancelChildOrderParam()
+	param.Child_order_acceptance_id = id
+	return client.CancelChildOrderWithContext(ctx, param)
+}
+
+func (client *Client) CancelParentOrderById(id string) error {
+	return client.CancelParentOrderByIdWithContext(context.Background(), id)
+}
+
+func (client *Client) CancelParentOrderByIdWithContext(ctx context.Context, id string) error {
+	param := NewCancelParentOrderParam()
+	param.Parent_order_id = id
+	return client.CancelParentOrderWithContext(ctx, param)
+}
+
+func (client *Client) CancelParentOrderByAcceptanceId(id string) error {
+	return client.CancelParentOrderByAcceptanceIdWithContext(context.Background(), id)
+}
+
+func (client *Client) CancelParentOrderByAcceptanceIdWithContext(ctx context.Context, id string) error {
+	param := NewCancelParentOrderParam()
+	param.Parent_order_acceptance_id = id
+	return client.CancelParentOrderWithContext(ctx, param)
+}
+
+/*
+ * CancelAllOrders cancels every child order and every active parent order of
+ * the product. It tries every order even if some fail and returns all
+ * failures joined. Orders that completed or were canceled in the meantime
+ * (any 4xx but authentication and rate limit errors) don't count as failures.
+ */
+func (client *Client) CancelAllOrders() error {
+	return client.CancelAllOrdersWithContext(context.Background())
+}
+
+func (client *Client) CancelAllOrdersWithContext(ctx context.Context) error {
+	errs := make([]error, 0)
+	if err := client.CancelAllChildOrdersWithContext(ctx, NewCancelAllChildOrdersParam()); err != nil {
+		errs = append(errs, err)
+	}
+
+	/* Walk every page, not only the first one, before cancelling */
+	param := NewGetParentOrdersParam()
+	param.Parent_order_state = ACTIVE
+	ids := make([]string, 0)
+	it := client.IterParentOrders(ctx, param)
+	for it.Next() {
+		ids = append(ids, it.Value().Parent_order_id)
+	}
+	if err := it.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, id := range ids {
+		err := client.CancelParentOrderByIdWithContext(ctx, id)
+		if err != nil && !isClientError(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (client *Client) GetBalanceByCurrency(currency string) (*GetBalanceResponse, error) {
+	return client.GetBalanceByCurrencyWithContext(context.Background(), currency)
+}
+
+func (client *Client) GetBalanceByCurrencyWithContext(ctx context.Context, currency string) (*GetBalanceResponse, error) {
+	balances, err := client.GetBalanceWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range balances {
+		if balances[i].Currency_code == currency {
+			return &balances[i], nil
+		}
+	}
+	return nil, fmt.Errorf("not found currency: %v", currency)
+}
@@ -0,0 +1,117 @@
+package bitflyerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCancelAllOrdersTriesEveryOrder(t *testing.T) {
+	var mu sync.Mutex
+	canceled := make([]string, 0)
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/me/cancelallchildorders":
+		case "/v1/me/getparentorders":
+			fmt.Fprint(w, `[{"id":4,"parent_order_id":"P4"},{"id":3,"parent_order_id":"P3"},{"id":2,"parent_order_id":"P2"},{"id":1,"parent_order_id":"P1"}]`)
+		case "/v1/me/cancelparentorder":
+			var param CancelParentOrderParam
+			json.NewDecoder(r.Body).Decode(&param)
+			mu.Lock()
+			canceled = append(canceled, param.Parent_order_id)
+			mu.Unlock()
+			switch param.Parent_order_id {
+			case "P3":
+				/* Completed between listing and cancelling */
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"status":-111,"error_message":"Order not found"}`)
+			case "P2":
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		default:
+			t.Errorf("unexpected request %v", r.URL.Path)
+		}
+	})
+
+	err := client.CancelAllOrdersWithContext(context.Background())
+	if len(canceled) != 4 {
+		t.Errorf("canceled %v, want every parent order", canceled)
+	}
+	apiErr, ok := asAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("CancelAllOrders() = %v, want the 500 of P2", err)
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 1 {
+		t.Errorf("CancelAllOrders() = %v, want exactly one failure", err)
+	}
+}
+
+/* parentOrdersHandler lists parent orders 1..250, one per hour back from 2020-01-01, 250 the newest */
+func parentOrdersHandler(t *testing.T, pages *[]string) http.HandlerFunc {
+	newest := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/me/getparentorder":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":-111,"error_message":"Order not found"}`)
+		case "/v1/me/getparentorders":
+			*pages = append(*pages, r.URL.Query().Get("before"))
+			before := int64(251)
+			if s := r.URL.Query().Get("before"); s != "" {
+				before, _ = strconv.ParseInt(s, 10, 64)
+			}
+			count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+			orders := make([]map[string]interface{}, 0)
+			for id := before - 1; 0 < id && len(orders) < count; id-- {
+				date := newest.Add(-time.Duration(250-id) * time.Hour)
+				orders = append(orders, map[string]interface{}{
+					"id":                         id,
+					"parent_order_acceptance_id": fmt.Sprintf("JRF-%d", id),
+					"parent_order_state":         COMPLETED,
+					"parent_order_date":          date.Format(bitflyerTimeLayout),
+				})
+			}
+			json.NewEncoder(w).Encode(orders)
+		default:
+			t.Errorf("unexpected request %v", r.URL.Path)
+		}
+	}
+}
+
+func TestGetParentOrderByAcceptanceIdFallback(t *testing.T) {
+	/* Order 100 is 150 hours old, on the second page */
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Add(-150 * time.Hour)
+	tests := []struct {
+		name  string
+		id    string
+		since time.Time
+		found bool
+		pages []string
+	}{
+		{"found on the second page", "JRF-120", since, true, []string{"", "151"}},
+		{"stops at since", "JRF-20", since, false, []string{"", "151"}},
+		{"zero since walks every page", "JRF-20", time.Time{}, true, []string{"", "151", "51"}},
+		{"not listed", "JRF-0", time.Time{}, false, []string{"", "151", "51"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []string
+			client := newStubClient(t, parentOrdersHandler(t, &pages))
+			order, err := client.GetParentOrderByAcceptanceIdWithContext(context.Background(), tt.id, FX_BTC_JPY, tt.since)
+			if tt.found && (err != nil || order.Parent_order_acceptance_id != tt.id) {
+				t.Errorf("GetParentOrderByAcceptanceId() = %+v, %v", order, err)
+			}
+			if !tt.found && !IsOrderNotFound(err) {
+				t.Errorf("GetParentOrderByAcceptanceId() = %+v, %v, want OrderNotFoundError", order, err)
+			}
+			if fmt.Sprint(pages) != fmt.Sprint(tt.pages) {
+				t.Errorf("pages before %q, want %q", pages, tt.pages)
+			}
+		})
+	}
+}
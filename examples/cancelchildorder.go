@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/comail/colog"
+	"log"
+	"os"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+)
+
+func main() {
+	/* Init logging */
+	colog.Register()
+	colog.SetDefaultLevel(colog.LDebug)
+	colog.SetFlags(log.Ldate | log.Ltime | log.Lmicroseconds | log.Lshortfile)
+	colog.SetMinLevel(colog.LDebug)
+
+	apiKey := os.Getenv("BITFLYER_API_KEY")
+	apiSecret := os.Getenv("BITFLYER_API_SECRET")
+
+	bfclient, err := bfapi.New(apiKey, apiSecret,
+		bfapi.WithLogger(bfapi.NewStdLogger(nil, bfapi.LevelDebug)))
+	if err != nil {
+		log.Fatal("Falied to new bitflyerclient")
+	}
+
+	id := "JRF20171212-122518-078461"
+	if err := bfclient.CancelChildOrderByAcceptanceId(id); err != nil {
+		log.Println(err)
+	}
+
+	if err := bfclient.CancelAllOrders(); err != nil {
+		log.Println(err)
+	}
+}
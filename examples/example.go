@@ -19,7 +19,8 @@ func main() {
 	apiKey := os.Getenv("BITFLYER_API_KEY")
 	apiSecret := os.Getenv("BITFLYER_API_SECRET")
 
-	client, err := bfapi.New(apiKey, apiSecret)
+	client, err := bfapi.New(apiKey, apiSecret,
+		bfapi.WithLogger(bfapi.NewStdLogger(nil, bfapi.LevelDebug)))
 	if err != nil {
 		log.Fatal("Falied to new bitflyerclient")
 	}
@@ -16,21 +16,22 @@ func main() {
 		log.Fatal("Falied to new bitflyerclient")
 	}
 
+	minSize := bitflyerclient.DecimalFromInt(10)
 	board, err := bfclient.GetBoard()
 	if err != nil {
 		log.Println(err)
 	}
 
 	for i, bids := range board.Bids {
-		if 10 < bids.Size {
-			log.Printf("[%d] Price: %f, Size: %f\n",
+		if bids.Size.GreaterThan(minSize) {
+			log.Printf("[%d] Price: %v, Size: %v\n",
 				i, bids.Price, bids.Size)
 		}
 	}
 	log.Println("-----------")
 	for i, asks := range board.Asks {
-		if 10 < asks.Size {
-			log.Printf("[%d] Price: %f, Size: %f\n",
+		if asks.Size.GreaterThan(minSize) {
+			log.Printf("[%d] Price: %v, Size: %v\n",
 				i, asks.Price, asks.Size)
 		}
 	}
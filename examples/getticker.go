@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+)
+
+func main() {
+	apiKey := os.Getenv("BITFLYER_API_KEY")
+	apiSecret := os.Getenv("BITFLYER_API_SECRET")
+
+	bfclient, err := bfapi.New(apiKey, apiSecret)
+	if err != nil {
+		log.Fatal("Falied to new bitflyerclient")
+	}
+
+	health, err := bfclient.GetBoardState()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if health.Health == bfapi.HEALTH_STOP || health.State != bfapi.BOARD_RUNNING {
+		log.Fatalf("Exchange is not available: %v %v\n", health.Health, health.State)
+	}
+
+	ticker, err := bfclient.GetTicker()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Bid: %v, Ask: %v, LTP: %v\n", ticker.Best_bid, ticker.Best_ask, ticker.Ltp)
+}
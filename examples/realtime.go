@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+	"github.com/fgken/bitflyer-api-sdk-go/realtime"
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	rtclient := realtime.New(realtime.WithLogger(bfapi.NewStdLogger(nil, bfapi.LevelInfo)))
+	tickers := rtclient.SubscribeTicker(bfapi.FX_BTC_JPY)
+	executions := rtclient.SubscribeExecutions(bfapi.FX_BTC_JPY)
+
+	go func() {
+		if err := rtclient.Run(ctx); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	for {
+		select {
+		case ticker, ok := <-tickers:
+			if !ok {
+				return
+			}
+			log.Printf("Bid: %v, Ask: %v\n", ticker.Best_bid, ticker.Best_ask)
+		case execs, ok := <-executions:
+			if !ok {
+				return
+			}
+			for _, exec := range execs {
+				log.Printf("%v %v @ %v\n", exec.Side, exec.Size, exec.Price)
+			}
+		}
+	}
+}
@@ -18,7 +18,8 @@ func main() {
 	apiKey := os.Getenv("BITFLYER_API_KEY")
 	apiSecret := os.Getenv("BITFLYER_API_SECRET")
 
-	bfclient, err := bfapi.New(apiKey, apiSecret)
+	bfclient, err := bfapi.New(apiKey, apiSecret,
+		bfapi.WithLogger(bfapi.NewStdLogger(nil, bfapi.LevelDebug)))
 	if err != nil {
 		log.Fatal("Falied to new bitflyerclient")
 	}
@@ -26,20 +27,20 @@ func main() {
 	param := bfapi.NewSendChildOrderParam()
 	param.Child_order_type = bfapi.MARKET
 	param.Side = bfapi.BUY
-	param.Size = 0.001
+	param.Size = bfapi.MustParseDecimal("0.01")
 	resp, err := bfclient.SendChildOrder(param)
 	if err != nil {
 		log.Println(err)
 	}
 	log.Println(resp)
 
-	bfclient.SendChildOrderMarket(bfapi.BUY, 0.001)
+	bfclient.SendChildOrderMarket(bfapi.BUY, 0.01)
 	if err != nil {
 		log.Println(err)
 	}
 	log.Println(resp)
 
-	bfclient.SendChildOrderLimit(bfapi.BUY, 1000000, 0.001)
+	bfclient.SendChildOrderLimit(bfapi.BUY, 1000000, 0.01)
 	if err != nil {
 		log.Println(err)
 	}
@@ -18,7 +18,8 @@ func main() {
 	apiKey := os.Getenv("BITFLYER_API_KEY")
 	apiSecret := os.Getenv("BITFLYER_API_SECRET")
 
-	bfclient, err := bfapi.New(apiKey, apiSecret)
+	bfclient, err := bfapi.New(apiKey, apiSecret,
+		bfapi.WithLogger(bfapi.NewStdLogger(nil, bfapi.LevelDebug)))
 	if err != nil {
 		log.Fatal("Falied to new bitflyerclient")
 	}
@@ -28,8 +29,8 @@ func main() {
 	parentOrder := bfapi.ParentOrder{
 		Condition_type: bfapi.STOP,
 		Side:           bfapi.BUY,
-		Size:           0.001,
-		Trigger_price:  3000000,
+		Size:           bfapi.MustParseDecimal("0.01"),
+		Trigger_price:  bfapi.DecimalFromInt(3000000),
 	}
 	param.Parameters = append(param.Parameters, parentOrder)
 	resp, err := bfclient.SendParentOrder(param)
@@ -38,21 +39,37 @@ func main() {
 	}
 	log.Println(resp)
 
-	resp, err = bfclient.SendParentOrderStop(bfapi.BUY, 3100000, 0.001)
+	resp, err = bfclient.SendParentOrderStop(bfapi.BUY, 3100000, 0.01)
 	if err != nil {
 		log.Println(err)
 	}
 	log.Println(resp)
 
 	var price float64 = 3000000
-	resp, err = bfclient.SendParentOrderIFDOCO(bfapi.STOP, bfapi.BUY, price, price+1000, price-500, 0.001)
+	resp, err = bfclient.SendParentOrderIFDOCO(bfapi.STOP, bfapi.BUY, price, price+1000, price-500, 0.01)
 	if err != nil {
 		log.Println(err)
 	}
 	log.Println(resp)
 
 	price = 1000000
-	resp, err = bfclient.SendParentOrderIFDOCO(bfapi.LIMIT, bfapi.BUY, price, price+1000, price-500, 0.001)
+	resp, err = bfclient.SendParentOrderIFDOCO(bfapi.LIMIT, bfapi.BUY, price, price+1000, price-500, 0.01)
+	if err != nil {
+		log.Println(err)
+	}
+	log.Println(resp)
+
+	size := bfapi.MustParseDecimal("0.01")
+	param, err = bfapi.NewParentOrder().
+		IfLimit(bfapi.BUY, bfapi.DecimalFromInt(1000000), size).
+		Then().
+		OCO(bfapi.LimitLeg(bfapi.SELL, bfapi.DecimalFromInt(1001000), size),
+			bfapi.StopLimitLeg(bfapi.SELL, bfapi.DecimalFromInt(999000), bfapi.DecimalFromInt(999500), size)).
+		Build()
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp, err = bfclient.SendParentOrder(param)
 	if err != nil {
 		log.Println(err)
 	}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+	"github.com/fgken/bitflyer-api-sdk-go/realtime"
+	"github.com/k0kubun/pp"
+)
+
+func main() {
+	apiKey := os.Getenv("BITFLYER_API_KEY")
+	apiSecret := os.Getenv("BITFLYER_API_SECRET")
+
+	bfclient, err := bfapi.New(apiKey, apiSecret)
+	if err != nil {
+		log.Fatal("Falied to new bitflyerclient")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	/* Wake up on order events instead of polling every 5 seconds */
+	rtclient := realtime.New(realtime.WithAuth(apiKey, apiSecret))
+	events := realtime.NewOrderEventBroker(rtclient)
+	go rtclient.Run(ctx)
+
+	resp, err := bfclient.SendChildOrderLimitWithContext(ctx, bfapi.BUY, 1000000, 0.01)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	order, err := bfclient.WaitForChildOrder(ctx, resp.Child_order_acceptance_id, nil,
+		bfapi.WithOrderEvents(events))
+	if err != nil {
+		log.Fatal(err)
+	}
+	pp.Println(order)
+}
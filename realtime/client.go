@@ -0,0 +1,448 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	Endpoint = "wss://ws.lightstream.bitflyer.com/json-rpc"
+)
+
+/* --- Channel names --- */
+func BoardSnapshotChannel(productCode string) string {
+	return "lightning_board_snapshot_" + productCode
+}
+
+func BoardChannel(productCode string) string {
+	return "lightning_board_" + productCode
+}
+
+func TickerChannel(productCode string) string {
+	return "lightning_ticker_" + productCode
+}
+
+func ExecutionsChannel(productCode string) string {
+	return "lightning_executions_" + productCode
+}
+
+/*
+ * Client is a JSON-RPC 2.0 client of bitFlyer's Realtime API.
+ * Subscribe to channels, then call Run; Run keeps the connection alive,
+ * reconnects and resubscribes until ctx is done, and closes every
+ * subscribed Go channel when it returns.
+ * Messages are dropped for a subscriber whose Go channel is full, size the
+ * buffer with WithBufferSize to absorb bursts and watch WithGapHandler.
+ */
+type Client struct {
+	endpoint     string
+	dialer       *websocket.Dialer
+	logger       bfapi.Logger
+	bufferSize   int
+	readTimeout  time.Duration
+	minReconnect time.Duration
+	maxReconnect time.Duration
+	apiKey       string
+	apiSecret    string
+	gapHandler   func(Gap)
+
+	mu      sync.Mutex
+	subs    map[string][]*subscription
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	nextId  int64
+	running bool
+	closed  bool
+	resumed bool /* a previous session was subscribed, so messages may have been missed */
+}
+
+type subscription struct {
+	channel string
+	deliver func(message json.RawMessage) error
+	close   func()
+}
+
+/* --- Client options --- */
+type Option func(*Client)
+
+/* WithEndpoint overrides Endpoint, e.g. to point at a local WebSocket stub. */
+func WithEndpoint(endpoint string) Option {
+	return func(c *Client) {
+		c.endpoint = endpoint
+	}
+}
+
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
+func WithLogger(logger bfapi.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+/* WithBufferSize sets the capacity of every subscribed Go channel, 64 by default. */
+func WithBufferSize(size int) Option {
+	return func(c *Client) {
+		c.bufferSize = size
+	}
+}
+
+/* WithReadTimeout sets how long the connection may stay silent before reconnecting. */
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.readTimeout = timeout
+	}
+}
+
+/*
+ * WithGapHandler is called whenever messages of a channel were lost: when a
+ * subscriber's buffer was full, and for every channel after a reconnect.
+ * Consumers of diffs such as SubscribeBoard should re-seed from a snapshot then.
+ * The handler runs on the read loop and must not block.
+ */
+func WithGapHandler(handler func(Gap)) Option {
+	return func(c *Client) {
+		c.gapHandler = handler
+	}
+}
+
+func WithReconnectBackoff(min, max time.Duration) Option {
+	return func(c *Client) {
+		c.minReconnect = min
+		c.maxReconnect = max
+	}
+}
+
+func New(opts ...Option) *Client {
+	c := &Client{
+		endpoint:     Endpoint,
+		dialer:       websocket.DefaultDialer,
+		logger:       nopLogger{},
+		bufferSize:   64,
+		readTimeout:  time.Minute,
+		minReconnect: time.Second,
+		maxReconnect: time.Minute,
+		subs:         make(map[string][]*subscription),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = nopLogger{}
+	}
+	return c
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(level bfapi.LogLevel, msg string, fields ...bfapi.LogField) {}
+
+/* log mirrors bfapi.Client's, so NewStdLogger reports the call site */
+func (c *Client) log(level bfapi.LogLevel, msg string, fields ...bfapi.LogField) {
+	c.logger.Log(level, msg, fields...)
+}
+
+/* --- Gaps --- */
+/* Gap reports that messages of Channel may have been missed. */
+type Gap struct {
+	Channel string
+	Reason  string
+}
+
+/* Gap Reason */
+const (
+	GAP_DROPPED   = "DROPPED"   /* the subscriber's buffer was full */
+	GAP_RECONNECT = "RECONNECT" /* messages published while disconnected are lost */
+)
+
+func (c *Client) gap(channel, reason string) {
+	c.log(bfapi.LevelWarn, "gap", bfapi.Field("channel", channel), bfapi.Field("reason", reason))
+	if c.gapHandler != nil {
+		c.gapHandler(Gap{Channel: channel, Reason: reason})
+	}
+}
+
+/* --- Typed subscriptions --- */
+func (c *Client) SubscribeBoardSnapshot(productCode string) <-chan *bfapi.GetBoardResponse {
+	return subscribeTyped[*bfapi.GetBoardResponse](c, BoardSnapshotChannel(productCode))
+}
+
+/*
+ * SubscribeBoard delivers board diffs, a size of 0 means the price level was removed.
+ * Diffs can't be applied across a gap, so re-seed an OrderBook from a snapshot
+ * on every Gap of this channel reported to WithGapHandler.
+ */
+func (c *Client) SubscribeBoard(productCode string) <-chan *bfapi.GetBoardResponse {
+	return subscribeTyped[*bfapi.GetBoardResponse](c, BoardChannel(productCode))
+}
+
+func (c *Client) SubscribeTicker(productCode string) <-chan *bfapi.GetTickerResponse {
+	return subscribeTyped[*bfapi.GetTickerResponse](c, TickerChannel(productCode))
+}
+
+func (c *Client) SubscribeExecutions(productCode string) <-chan []bfapi.GetPublicExecutionsResponse {
+	return subscribeTyped[[]bfapi.GetPublicExecutionsResponse](c, ExecutionsChannel(productCode))
+}
+
+/* ErrBufferFull is returned by deliver when a message is dropped because its subscriber is too slow. */
+var ErrBufferFull = errors.New("realtime: subscriber buffer full, message dropped")
+
+/*
+ * subscribeTyped decodes every message of channel into T.
+ * Delivery never blocks the read loop: when the Go channel is full the
+ * message is dropped and reported as a Gap, so one slow consumer can't
+ * stall the connection.
+ */
+func subscribeTyped[T any](c *Client, channel string) <-chan T {
+	ch := make(chan T, c.bufferSize)
+	c.subscribe(&subscription{
+		channel: channel,
+		deliver: func(message json.RawMessage) error {
+			var value T
+			if err := json.Unmarshal(message, &value); err != nil {
+				return err
+			}
+			select {
+			case ch <- value:
+				return nil
+			default:
+				return ErrBufferFull
+			}
+		},
+		close: func() { close(ch) },
+	})
+	return ch
+}
+
+/* subscribe registers sub and, when already connected, sends the subscribe request */
+func (c *Client) subscribe(sub *subscription) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		sub.close()
+		return
+	}
+	subscribed := 0 < len(c.subs[sub.channel])
+	c.subs[sub.channel] = append(c.subs[sub.channel], sub)
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn != nil && !subscribed {
+		if _, err := c.call(conn, "subscribe", channelParams{Channel: sub.channel}); err != nil {
+			c.log(bfapi.LevelError, "subscribe", bfapi.Field("channel", sub.channel), bfapi.Field("error", err))
+		}
+	}
+}
+
+/* --- JSON-RPC --- */
+type rpcRequest struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	Id      int64       `json:"id"`
+}
+
+type channelParams struct {
+	Channel string `json:"channel"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("realtime: json-rpc error %v: %v", e.Code, e.Message)
+}
+
+type rpcMessage struct {
+	Id     *int64          `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+	Params struct {
+		Channel string          `json:"channel"`
+		Message json.RawMessage `json:"message"`
+	} `json:"params"`
+}
+
+func (c *Client) call(conn *websocket.Conn, method string, params interface{}) (int64, error) {
+	c.mu.Lock()
+	c.nextId++
+	id := c.nextId
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return id, conn.WriteJSON(rpcRequest{Jsonrpc: "2.0", Method: method, Params: params, Id: id})
+}
+
+/* --- Connection loop --- */
+/* Run can be called only once per Client */
+var ErrAlreadyRunning = errors.New("realtime: client is already running or closed")
+
+/* Run connects and dispatches messages until ctx is done. It always returns a non-nil error. */
+func (c *Client) Run(ctx context.Context) error {
+	c.mu.Lock()
+	if c.running || c.closed {
+		c.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	c.running = true
+	c.mu.Unlock()
+	defer c.shutdown()
+
+	backoff := c.minReconnect
+	for {
+		start := time.Now()
+		err := c.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.log(bfapi.LevelWarn, "connection lost", bfapi.Field("endpoint", c.endpoint), bfapi.Field("error", err))
+
+		/* A session that stayed up for a while resets the backoff */
+		if c.maxReconnect < time.Since(start) {
+			backoff = c.minReconnect
+		}
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if backoff *= 2; c.maxReconnect < backoff {
+			backoff = c.maxReconnect
+		}
+	}
+}
+
+func (c *Client) shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	c.conn = nil
+	for channel, subs := range c.subs {
+		for _, sub := range subs {
+			sub.close()
+		}
+		delete(c.subs, channel)
+	}
+}
+
+func (c *Client) session(ctx context.Context) error {
+	conn, _, err := c.dialer.DialContext(ctx, c.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	/* Unblock ReadMessage when ctx is done */
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.writeMu.Lock()
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			c.writeMu.Unlock()
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	c.log(bfapi.LevelInfo, "connected", bfapi.Field("endpoint", c.endpoint))
+	if c.apiKey != "" {
+		if err := c.auth(conn); err != nil {
+			return err
+		}
+	}
+
+	/* From here on subscribe() sends new subscriptions by itself */
+	c.mu.Lock()
+	c.conn = conn
+	channels := make([]string, 0, len(c.subs))
+	for channel := range c.subs {
+		channels = append(channels, channel)
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+	}()
+
+	for _, channel := range channels {
+		if _, err := c.call(conn, "subscribe", channelParams{Channel: channel}); err != nil {
+			return err
+		}
+	}
+	if c.resumed {
+		for _, channel := range channels {
+			c.gap(channel, GAP_RECONNECT)
+		}
+	}
+	c.resumed = true
+
+	for {
+		message, err := c.read(conn)
+		if err != nil {
+			return err
+		}
+		c.dispatch(message)
+	}
+}
+
+func (c *Client) read(conn *websocket.Conn) (*rpcMessage, error) {
+	if 0 < c.readTimeout {
+		conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+	}
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	var message rpcMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		/* Skip a broken message rather than dropping the connection */
+		c.log(bfapi.LevelError, "unmarshal message", bfapi.Field("error", err))
+	}
+	return &message, nil
+}
+
+func (c *Client) dispatch(message *rpcMessage) {
+	if message.Error != nil {
+		c.log(bfapi.LevelError, "json-rpc error", bfapi.Field("id", message.Id), bfapi.Field("error", message.Error))
+		return
+	}
+	if message.Method != "channelMessage" {
+		return
+	}
+
+	c.mu.Lock()
+	subs := c.subs[message.Params.Channel]
+	c.mu.Unlock()
+	for _, sub := range subs {
+		err := sub.deliver(message.Params.Message)
+		switch {
+		case err == ErrBufferFull:
+			c.gap(sub.channel, GAP_DROPPED)
+		case err != nil:
+			c.log(bfapi.LevelError, "deliver message", bfapi.Field("channel", sub.channel), bfapi.Field("error", err))
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+	"github.com/gorilla/websocket"
+)
+
+const testTimeout = 5 * time.Second
+
+/* stubServer is a local JSON-RPC WebSocket server, every accepted connection is sent to conns */
+type stubServer struct {
+	*httptest.Server
+	conns chan *stubConn
+}
+
+type stubConn struct {
+	conn     *websocket.Conn
+	requests chan stubRequest /* closed when the connection is gone */
+}
+
+type stubRequest struct {
+	Method string
+	Params channelParams
+}
+
+func newStubServer(t *testing.T) *stubServer {
+	s := &stubServer{conns: make(chan *stubConn, 8)}
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		sc := &stubConn{conn: conn, requests: make(chan stubRequest, 16)}
+		s.conns <- sc
+		defer close(sc.requests)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var request stubRequest
+			if err := json.Unmarshal(data, &request); err != nil {
+				t.Errorf("unmarshal request: %v", err)
+				return
+			}
+			sc.requests <- request
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *stubServer) endpoint() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *stubServer) accept(t *testing.T) *stubConn {
+	t.Helper()
+	select {
+	case sc := <-s.conns:
+		t.Cleanup(func() { sc.conn.Close() })
+		return sc
+	case <-time.After(testTimeout):
+		t.Fatal("no connection")
+		return nil
+	}
+}
+
+/* expectSubscribe waits for subscribe requests of all channels, in any order */
+func (sc *stubConn) expectSubscribe(t *testing.T, channels ...string) {
+	t.Helper()
+	want := make(map[string]bool)
+	for _, channel := range channels {
+		want[channel] = true
+	}
+	for 0 < len(want) {
+		select {
+		case request, ok := <-sc.requests:
+			if !ok {
+				t.Fatal("connection closed before subscribe")
+			}
+			if request.Method != "subscribe" || !want[request.Params.Channel] {
+				t.Fatalf("unexpected request %v %v", request.Method, request.Params.Channel)
+			}
+			delete(want, request.Params.Channel)
+		case <-time.After(testTimeout):
+			t.Fatalf("no subscribe for %v", want)
+		}
+	}
+}
+
+func (sc *stubConn) send(t *testing.T, channel, message string) {
+	t.Helper()
+	data := fmt.Sprintf(`{"jsonrpc":"2.0","method":"channelMessage","params":{"channel":%q,"message":%s}}`, channel, message)
+	if err := sc.conn.WriteMessage(websocket.TextMessage, []byte(data)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case value, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed")
+		}
+		return value
+	case <-time.After(testTimeout):
+		t.Fatal("no message")
+	}
+	var zero T
+	return zero
+}
+
+/* startClient runs c until the test ends, the result of Run is sent to the returned channel */
+func startClient(t *testing.T, c *Client) (context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		result <- c.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-finished
+	})
+	return cancel, result
+}
+
+func TestSubscribeAndDispatch(t *testing.T) {
+	s := newStubServer(t)
+	c := New(WithEndpoint(s.endpoint()))
+	tickers := c.SubscribeTicker(bfapi.BTC_JPY)
+	executions := c.SubscribeExecutions(bfapi.BTC_JPY)
+	startClient(t, c)
+
+	sc := s.accept(t)
+	sc.expectSubscribe(t, TickerChannel(bfapi.BTC_JPY), ExecutionsChannel(bfapi.BTC_JPY))
+
+	/* Channels subscribed while connected are sent right away */
+	boards := c.SubscribeBoard(bfapi.BTC_JPY)
+	sc.expectSubscribe(t, BoardChannel(bfapi.BTC_JPY))
+
+	sc.send(t, TickerChannel(bfapi.BTC_JPY), `{"product_code":"BTC_JPY","best_bid":100.5,"best_ask":101}`)
+	sc.send(t, ExecutionsChannel(bfapi.BTC_JPY), `[{"id":1,"side":"BUY","price":100,"size":0.01},{"id":2,"side":"SELL","price":99,"size":0.02}]`)
+	sc.send(t, BoardChannel(bfapi.BTC_JPY), `{"mid_price":100,"bids":[{"price":99,"size":0}],"asks":[]}`)
+
+	ticker := receive(t, tickers)
+	if ticker.Product_code != bfapi.BTC_JPY || !ticker.Best_bid.Equal(bfapi.MustParseDecimal("100.5")) {
+		t.Errorf("ticker = %+v", ticker)
+	}
+	execs := receive(t, executions)
+	if len(execs) != 2 || execs[1].Id != 2 || !execs[1].Size.Equal(bfapi.MustParseDecimal("0.02")) {
+		t.Errorf("executions = %+v", execs)
+	}
+	board := receive(t, boards)
+	if len(board.Bids) != 1 || !board.Bids[0].Size.IsZero() {
+		t.Errorf("board = %+v", board)
+	}
+}
+
+func TestResubscribeAfterDisconnect(t *testing.T) {
+	s := newStubServer(t)
+	c := New(WithEndpoint(s.endpoint()), WithReconnectBackoff(10*time.Millisecond, 20*time.Millisecond))
+	tickers := c.SubscribeTicker(bfapi.FX_BTC_JPY)
+	startClient(t, c)
+
+	first := s.accept(t)
+	first.expectSubscribe(t, TickerChannel(bfapi.FX_BTC_JPY))
+	first.conn.Close()
+
+	second := s.accept(t)
+	second.expectSubscribe(t, TickerChannel(bfapi.FX_BTC_JPY))
+	second.send(t, TickerChannel(bfapi.FX_BTC_JPY), `{"product_code":"FX_BTC_JPY","tick_id":7}`)
+	if ticker := receive(t, tickers); ticker.Tick_id != 7 {
+		t.Errorf("ticker = %+v", ticker)
+	}
+}
+
+func TestSlowSubscriberDoesNotBlock(t *testing.T) {
+	s := newStubServer(t)
+	c := New(WithEndpoint(s.endpoint()), WithBufferSize(1))
+	slow := c.SubscribeTicker(bfapi.BTC_JPY)
+	fast := c.SubscribeTicker(bfapi.ETH_JPY)
+	startClient(t, c)
+
+	sc := s.accept(t)
+	sc.expectSubscribe(t, TickerChannel(bfapi.BTC_JPY), TickerChannel(bfapi.ETH_JPY))
+	for i := 1; i <= 3; i++ {
+		sc.send(t, TickerChannel(bfapi.BTC_JPY), fmt.Sprintf(`{"tick_id":%d}`, i))
+	}
+	sc.send(t, TickerChannel(bfapi.ETH_JPY), `{"tick_id":10}`)
+
+	if ticker := receive(t, fast); ticker.Tick_id != 10 {
+		t.Errorf("fast ticker = %+v", ticker)
+	}
+	/* Only the first message fit into the buffer */
+	if ticker := receive(t, slow); ticker.Tick_id != 1 {
+		t.Errorf("slow ticker = %+v", ticker)
+	}
+	select {
+	case ticker := <-slow:
+		t.Errorf("unexpected ticker %+v", ticker)
+	default:
+	}
+}
+
+func TestRunClosesChannels(t *testing.T) {
+	s := newStubServer(t)
+	c := New(WithEndpoint(s.endpoint()))
+	tickers := c.SubscribeTicker(bfapi.BTC_JPY)
+	cancel, done := startClient(t, c)
+
+	sc := s.accept(t)
+	sc.expectSubscribe(t, TickerChannel(bfapi.BTC_JPY))
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Run() = %v", err)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("Run did not return")
+	}
+	select {
+	case _, ok := <-tickers:
+		if ok {
+			t.Error("channel not closed")
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("channel not closed")
+	}
+
+	/* Subscribing after Run returned yields a closed channel, Run can't be restarted */
+	if _, ok := <-c.SubscribeTicker(bfapi.ETH_JPY); ok {
+		t.Error("channel not closed")
+	}
+	if err := c.Run(context.Background()); err != ErrAlreadyRunning {
+		t.Errorf("second Run() = %v", err)
+	}
+}
+
+func TestGapHandler(t *testing.T) {
+	s := newStubServer(t)
+	gaps := make(chan Gap, 16)
+	c := New(WithEndpoint(s.endpoint()), WithBufferSize(1),
+		WithReconnectBackoff(10*time.Millisecond, 20*time.Millisecond),
+		WithGapHandler(func(gap Gap) { gaps <- gap }))
+	boards := c.SubscribeBoard(bfapi.BTC_JPY)
+	startClient(t, c)
+
+	first := s.accept(t)
+	first.expectSubscribe(t, BoardChannel(bfapi.BTC_JPY))
+	first.send(t, BoardChannel(bfapi.BTC_JPY), `{"mid_price":1}`)
+	first.send(t, BoardChannel(bfapi.BTC_JPY), `{"mid_price":2}`)
+	if gap := receive(t, gaps); gap != (Gap{Channel: BoardChannel(bfapi.BTC_JPY), Reason: GAP_DROPPED}) {
+		t.Errorf("gap = %+v", gap)
+	}
+	receive(t, boards)
+
+	/* The first connection is no gap, every later one is */
+	first.conn.Close()
+	second := s.accept(t)
+	second.expectSubscribe(t, BoardChannel(bfapi.BTC_JPY))
+	if gap := receive(t, gaps); gap != (Gap{Channel: BoardChannel(bfapi.BTC_JPY), Reason: GAP_RECONNECT}) {
+		t.Errorf("gap = %+v", gap)
+	}
+	select {
+	case gap := <-gaps:
+		t.Errorf("unexpected gap %+v", gap)
+	default:
+	}
+}
@@ -0,0 +1,101 @@
+package realtime
+
+import (
+	"sync"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+)
+
+/*
+ * OrderEventBroker fans child_order_events and parent_order_events out to
+ * any number of subscribers. It implements bfapi.OrderEventSource, so it can
+ * be passed to bfapi.WithOrderEvents. The Client needs WithAuth.
+ */
+type OrderEventBroker struct {
+	mu     sync.Mutex
+	subs   map[chan bfapi.OrderEvent]struct{}
+	closed bool
+}
+
+/* NewOrderEventBroker subscribes to the private order channels of c, call it before c.Run. */
+func NewOrderEventBroker(c *Client) *OrderEventBroker {
+	broker := &OrderEventBroker{subs: make(map[chan bfapi.OrderEvent]struct{})}
+	childEvents := c.SubscribeChildOrderEvents()
+	parentEvents := c.SubscribeParentOrderEvents()
+
+	go func() {
+		defer broker.close()
+		for childEvents != nil || parentEvents != nil {
+			select {
+			case events, ok := <-childEvents:
+				if !ok {
+					childEvents = nil
+					continue
+				}
+				for _, event := range events {
+					broker.publish(bfapi.OrderEvent{
+						Child_order_acceptance_id: event.Child_order_acceptance_id,
+						Event_type:                event.Event_type,
+						Outstanding_size:          event.Outstanding_size,
+					})
+				}
+			case events, ok := <-parentEvents:
+				if !ok {
+					parentEvents = nil
+					continue
+				}
+				for _, event := range events {
+					broker.publish(bfapi.OrderEvent{
+						Child_order_acceptance_id:  event.Child_order_acceptance_id,
+						Parent_order_acceptance_id: event.Parent_order_acceptance_id,
+						Event_type:                 event.Event_type,
+					})
+				}
+			}
+		}
+	}()
+	return broker
+}
+
+/* publish never blocks, a slow subscriber misses events and has to rely on polling */
+func (broker *OrderEventBroker) publish(event bfapi.OrderEvent) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	for ch := range broker.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (broker *OrderEventBroker) close() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	broker.closed = true
+	for ch := range broker.subs {
+		close(ch)
+		delete(broker.subs, ch)
+	}
+}
+
+func (broker *OrderEventBroker) SubscribeOrderEvents() (<-chan bfapi.OrderEvent, func()) {
+	ch := make(chan bfapi.OrderEvent, 16)
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if broker.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	broker.subs[ch] = struct{}{}
+
+	return ch, func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		if _, ok := broker.subs[ch]; ok {
+			delete(broker.subs, ch)
+			close(ch)
+		}
+	}
+}
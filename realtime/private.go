@@ -0,0 +1,140 @@
+package realtime
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bfapi "github.com/fgken/bitflyer-api-sdk-go/bitflyerclient"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	ChildOrderEventsChannel  = "child_order_events"
+	ParentOrderEventsChannel = "parent_order_events"
+)
+
+/* Event Type */
+const (
+	EVENT_ORDER         = "ORDER"
+	EVENT_ORDER_FAILED  = "ORDER_FAILED"
+	EVENT_CANCEL        = "CANCEL"
+	EVENT_CANCEL_FAILED = "CANCEL_FAILED"
+	EVENT_EXECUTION     = "EXECUTION"
+	EVENT_EXPIRE        = "EXPIRE"
+	EVENT_TRIGGER       = "TRIGGER"
+	EVENT_COMPLETE      = "COMPLETE"
+)
+
+/* WithAuth authenticates every connection, which is required for private channels. */
+func WithAuth(apiKey, apiSecret string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+		c.apiSecret = apiSecret
+	}
+}
+
+type authParams struct {
+	Api_key   string `json:"api_key"`
+	Timestamp int64  `json:"timestamp"`
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+func (c *Client) newAuthParams() (*authParams, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	params := &authParams{
+		Api_key:   c.apiKey,
+		Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
+		Nonce:     hex.EncodeToString(b),
+	}
+	mac := hmac.New(sha256.New, []byte(c.apiSecret))
+	mac.Write([]byte(fmt.Sprintf("%d%s", params.Timestamp, params.Nonce)))
+	params.Signature = hex.EncodeToString(mac.Sum(nil))
+	return params, nil
+}
+
+/* auth sends the auth request and waits for its result */
+func (c *Client) auth(conn *websocket.Conn) error {
+	params, err := c.newAuthParams()
+	if err != nil {
+		return err
+	}
+	id, err := c.call(conn, "auth", params)
+	if err != nil {
+		return err
+	}
+
+	for {
+		message, err := c.read(conn)
+		if err != nil {
+			return err
+		}
+		if message.Id == nil || *message.Id != id {
+			c.dispatch(message)
+			continue
+		}
+		if message.Error != nil {
+			return message.Error
+		}
+		var ok bool
+		if err := json.Unmarshal(message.Result, &ok); err != nil || !ok {
+			return fmt.Errorf("realtime: auth failed: %s", message.Result)
+		}
+		c.log(bfapi.LevelInfo, "authenticated")
+		return nil
+	}
+}
+
+/* --- Private channel events --- */
+type ChildOrderEvent struct {
+	Product_code              string
+	Child_order_id            string
+	Child_order_acceptance_id string
+	Event_date                bfapi.BitflyerTime
+	Event_type                string
+	Child_order_type          string
+	Side                      string
+	Price                     bfapi.Decimal
+	Size                      bfapi.Decimal
+	Expire_date               bfapi.BitflyerTime
+	Reason                    string
+	Exec_id                   int64
+	Commission                bfapi.Decimal
+	Sfd                       bfapi.Decimal
+	Outstanding_size          bfapi.Decimal
+}
+
+type ParentOrderEvent struct {
+	Product_code               string
+	Parent_order_id            string
+	Parent_order_acceptance_id string
+	Event_date                 bfapi.BitflyerTime
+	Event_type                 string
+	Parent_order_type          string
+	Reason                     string
+	Child_order_type           string
+	Parameter_index            int
+	Child_order_acceptance_id  string
+	Side                       string
+	Price                      bfapi.Decimal
+	Size                       bfapi.Decimal
+	Expire_date                bfapi.BitflyerTime
+}
+
+/* SubscribeChildOrderEvents requires WithAuth. */
+func (c *Client) SubscribeChildOrderEvents() <-chan []ChildOrderEvent {
+	return subscribeTyped[[]ChildOrderEvent](c, ChildOrderEventsChannel)
+}
+
+/* SubscribeParentOrderEvents requires WithAuth. */
+func (c *Client) SubscribeParentOrderEvents() <-chan []ParentOrderEvent {
+	return subscribeTyped[[]ParentOrderEvent](c, ParentOrderEventsChannel)
+}